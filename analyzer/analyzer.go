@@ -0,0 +1,147 @@
+// Package analyzer provides go/analysis passes that check sum types built on sumtype.Caster
+// at vet time. Run them with "go vet -vettool=$(which sumtypevet) ./..." (see cmd/sumtypevet).
+package analyzer
+
+import (
+	"fmt"
+	"go/ast"
+	"go/types"
+
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/analysis/passes/inspect"
+	"golang.org/x/tools/go/ast/inspector"
+)
+
+// casterPath and casterName identify the generic sumtype.Caster type.
+const casterPath, casterName = "github.com/JeffreyRichter/sumtype", "Caster"
+
+// invalidProjection is the comment that opts a deliberately invalid projection (for example, in
+// a test of Caster.ValidateStructFields) out of the layout check; it must be on the type's line
+// or on the line above it.
+const invalidProjection = "//sumtype:invalid"
+
+// Analyzer reports projection structs whose layout doesn't match their sum type's Json struct.
+var Analyzer = &analysis.Analyzer{
+	Name: "sumtypelayout",
+	Doc: `check that sum type projections match their Json struct's layout
+
+Every struct whose first field's underlying type is sumtype.Caster[Json] is a projection of
+Json and must have the same number of fields, in the same order, with the same types, offsets
+and size as Json; exported fields must also have the same names as Json's fields. Unlike
+Caster.ValidateStructFields, this also catches projections that were never registered. Put a
+` + invalidProjection + ` comment on or above a deliberately invalid projection to opt out.`,
+	Requires: []*analysis.Analyzer{inspect.Analyzer},
+	Run:      runLayout,
+}
+
+func runLayout(pass *analysis.Pass) (any, error) {
+	inspect := pass.ResultOf[inspect.Analyzer].(*inspector.Inspector)
+	casters := casterTypes(pass, inspect)
+
+	inspect.Preorder([]ast.Node{(*ast.TypeSpec)(nil)}, func(n ast.Node) {
+		spec := n.(*ast.TypeSpec)
+		obj, ok := pass.TypesInfo.Defs[spec.Name].(*types.TypeName)
+		if !ok || obj.IsAlias() {
+			return
+		}
+		projection, ok := obj.Type().Underlying().(*types.Struct)
+		if !ok || projection.NumFields() == 0 {
+			return
+		}
+		json := jsonOf(projection.Field(0).Type(), casters)
+		if json == nil || types.Identical(json, obj.Type()) {
+			return // Not a projection or it's the Json struct itself
+		}
+		for _, file := range pass.Files {
+			if file.FileStart <= spec.Pos() && spec.Pos() < file.FileEnd && hasDirective(pass, file, spec.Pos(), invalidProjection) {
+				return
+			}
+		}
+		qualifier := types.RelativeTo(pass.Pkg)
+		if err := compareLayout(pass.TypesSizes, qualifier, json, obj.Type()); err != nil {
+			pass.Reportf(spec.Name.Pos(), "%s is not a valid projection of %s: %v",
+				obj.Name(), types.TypeString(json, qualifier), err)
+		}
+	})
+	return nil, nil
+}
+
+// casterTypes returns the package's defined types whose underlying type is sumtype.Caster[Json],
+// mapped to Json. go/types reports a defined type's underlying type as struct{}, so the
+// declarations' right-hand sides are consulted instead.
+func casterTypes(pass *analysis.Pass, inspect *inspector.Inspector) map[*types.TypeName]types.Type {
+	casters := map[*types.TypeName]types.Type{}
+	inspect.Preorder([]ast.Node{(*ast.TypeSpec)(nil)}, func(n ast.Node) {
+		spec := n.(*ast.TypeSpec)
+		if obj, ok := pass.TypesInfo.Defs[spec.Name].(*types.TypeName); ok {
+			if json := casterArg(pass.TypesInfo.TypeOf(spec.Type)); json != nil {
+				casters[obj] = json
+			}
+		}
+	})
+	return casters
+}
+
+// jsonOf returns Json if t is sumtype.Caster[Json] or a type defined as sumtype.Caster[Json].
+func jsonOf(t types.Type, casters map[*types.TypeName]types.Type) types.Type {
+	if json := casterArg(t); json != nil {
+		return json
+	}
+	if named, ok := types.Unalias(t).(*types.Named); ok {
+		return casters[named.Obj()]
+	}
+	return nil
+}
+
+// casterArg returns Json if t is an instance of sumtype.Caster[Json]; otherwise nil.
+func casterArg(t types.Type) types.Type {
+	named, ok := types.Unalias(t).(*types.Named)
+	if !ok || named.TypeArgs().Len() != 1 {
+		return nil
+	}
+	if obj := named.Origin().Obj(); obj.Pkg() == nil || obj.Pkg().Path() != casterPath || obj.Name() != casterName {
+		return nil
+	}
+	return named.TypeArgs().At(0)
+}
+
+// compareLayout returns an error describing the first difference between json's and
+// projection's layouts.
+func compareLayout(sizes types.Sizes, qualifier types.Qualifier, json, projection types.Type) error {
+	js, ok := json.Underlying().(*types.Struct)
+	if !ok {
+		return fmt.Errorf("%s is not a struct", json)
+	}
+	ps := projection.Underlying().(*types.Struct)
+	if js.NumFields() != ps.NumFields() {
+		return fmt.Errorf("has %d fields instead of %d", ps.NumFields(), js.NumFields())
+	}
+
+	jFields, pFields := structFields(js), structFields(ps)
+	jOffsets, pOffsets := sizes.Offsetsof(jFields), sizes.Offsetsof(pFields)
+	for f := 1; f < len(jFields); f++ { // Field 0 is the caster whose type differs by design
+		jf, pf := jFields[f], pFields[f]
+		switch {
+		case !types.Identical(jf.Type(), pf.Type()):
+			return fmt.Errorf("field #%d %s (%s) has a different type than %s (%s)", f,
+				pf.Name(), types.TypeString(pf.Type(), qualifier), jf.Name(), types.TypeString(jf.Type(), qualifier))
+		case jOffsets[f] != pOffsets[f]:
+			return fmt.Errorf("field #%d %s has offset %d instead of %d", f, pf.Name(), pOffsets[f], jOffsets[f])
+		case pf.Exported() && pf.Name() != jf.Name():
+			return fmt.Errorf("field #%d %s must be named %s", f, pf.Name(), jf.Name())
+		}
+	}
+	if js, ps := sizes.Sizeof(json), sizes.Sizeof(projection); js != ps {
+		return fmt.Errorf("size is %d instead of %d", ps, js)
+	}
+	return nil
+}
+
+// structFields returns the fields of s.
+func structFields(s *types.Struct) []*types.Var {
+	fields := make([]*types.Var, s.NumFields())
+	for f := range fields {
+		fields[f] = s.Field(f)
+	}
+	return fields
+}
@@ -0,0 +1,18 @@
+package analyzer_test
+
+import (
+	"testing"
+
+	"github.com/JeffreyRichter/sumtype/analyzer"
+	"golang.org/x/tools/go/analysis/analysistest"
+)
+
+// TestAnalyzer tests that mismatched projections are reported, registered with ValidateStructFields or not
+func TestAnalyzer(t *testing.T) {
+	analysistest.Run(t, analysistest.TestData(), analyzer.Analyzer, "layout")
+}
+
+// TestExhaustiveAnalyzer tests that switches on discriminators missing kinds are reported, across packages too
+func TestExhaustiveAnalyzer(t *testing.T) {
+	analysistest.Run(t, analysistest.TestData(), analyzer.ExhaustiveAnalyzer, "shapes", "useshapes", "tagged")
+}
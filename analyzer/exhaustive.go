@@ -0,0 +1,168 @@
+package analyzer
+
+import (
+	"fmt"
+	"go/ast"
+	"go/token"
+	"go/types"
+	"reflect"
+	"strings"
+
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/analysis/passes/inspect"
+	"golang.org/x/tools/go/ast/inspector"
+)
+
+// nonExhaustive is the comment that opts a switch out of the exhaustiveness check; it must be
+// on the switch statement's line or on the line above it.
+const nonExhaustive = "//sumtype:nonexhaustive"
+
+// ExhaustiveAnalyzer reports switch statements on a sum type's discriminator that don't have a
+// case for every kind constant.
+var ExhaustiveAnalyzer = &analysis.Analyzer{
+	Name: "sumtypeexhaustive",
+	Doc: `check that switches on a sum type's discriminator handle every kind
+
+A Json struct's discriminator is its field tagged sumtype:"discriminator" or, if no field is
+tagged, its field named Kind. Every constant of the discriminator's type declared in the type's
+package is a kind. A switch on a discriminator value must have a case for every kind, even if
+it has a default case (which handles kinds unknown when the code was written). Put a
+` + nonExhaustive + ` comment on or above the switch to opt out.`,
+	Requires:  []*analysis.Analyzer{inspect.Analyzer},
+	FactTypes: []analysis.Fact{new(kindsFact)},
+	Run:       runExhaustive,
+}
+
+// kindsFact is attached to a discriminator's type and records its kind constants.
+type kindsFact struct {
+	Kinds []kindConst
+}
+
+// kindConst is a kind constant's name and exact value.
+type kindConst struct {
+	Name, Value string
+}
+
+func (*kindsFact) AFact() {}
+
+func (f *kindsFact) String() string { return fmt.Sprintf("kinds%v", f.Kinds) }
+
+func runExhaustive(pass *analysis.Pass) (any, error) {
+	inspect := pass.ResultOf[inspect.Analyzer].(*inspector.Inspector)
+	exportKindsFacts(pass, inspect)
+
+	inspect.WithStack([]ast.Node{(*ast.SwitchStmt)(nil)}, func(n ast.Node, push bool, stack []ast.Node) bool {
+		sw := n.(*ast.SwitchStmt)
+		if !push || sw.Tag == nil {
+			return true
+		}
+		named, ok := types.Unalias(pass.TypesInfo.TypeOf(sw.Tag)).(*types.Named)
+		if !ok {
+			return true
+		}
+		var fact kindsFact
+		if !pass.ImportObjectFact(named.Obj(), &fact) || optedOut(pass, stack, sw) {
+			return true
+		}
+
+		handled := map[string]bool{}
+		for _, stmt := range sw.Body.List {
+			for _, expr := range stmt.(*ast.CaseClause).List {
+				if tv := pass.TypesInfo.Types[expr]; tv.Value != nil {
+					handled[tv.Value.ExactString()] = true
+				}
+			}
+		}
+		var missing []string
+		for _, k := range fact.Kinds {
+			if !handled[k.Value] {
+				missing = append(missing, k.Name)
+			}
+		}
+		if len(missing) > 0 {
+			pass.Reportf(sw.Pos(), "switch on %s is missing cases for %s",
+				types.TypeString(named, types.RelativeTo(pass.Pkg)), strings.Join(missing, ", "))
+		}
+		return true
+	})
+	return nil, nil
+}
+
+// exportKindsFacts attaches a kindsFact to the discriminator type of every Json struct declared
+// in the package so switches in this and importing packages can be checked.
+func exportKindsFacts(pass *analysis.Pass, inspect *inspector.Inspector) {
+	casters := casterTypes(pass, inspect)
+	inspect.Preorder([]ast.Node{(*ast.TypeSpec)(nil)}, func(n ast.Node) {
+		obj, ok := pass.TypesInfo.Defs[n.(*ast.TypeSpec).Name].(*types.TypeName)
+		if !ok {
+			return
+		}
+		s, ok := obj.Type().Underlying().(*types.Struct)
+		if !ok || s.NumFields() == 0 {
+			return
+		}
+		if json := jsonOf(s.Field(0).Type(), casters); json == nil || !types.Identical(json, obj.Type()) {
+			return // Only the Json struct identifies the discriminator
+		}
+		kindType := discriminator(s)
+		if kindType == nil || kindType.Obj().Pkg() != pass.Pkg {
+			return
+		}
+
+		var fact kindsFact
+		scope := pass.Pkg.Scope()
+		for _, name := range scope.Names() {
+			if c, ok := scope.Lookup(name).(*types.Const); ok && types.Identical(c.Type(), kindType) {
+				fact.Kinds = append(fact.Kinds, kindConst{Name: c.Name(), Value: c.Val().ExactString()})
+			}
+		}
+		if len(fact.Kinds) > 0 {
+			pass.ExportObjectFact(kindType.Obj(), &fact)
+		}
+	})
+}
+
+// discriminator returns the named type of the Json struct's discriminator field or nil.
+func discriminator(s *types.Struct) *types.Named {
+	field := -1
+	for f := range s.NumFields() {
+		tag := reflect.StructTag(s.Tag(f)).Get("sumtype")
+		if strings.Split(tag, ",")[0] == "discriminator" {
+			field = f
+			break
+		}
+		if s.Field(f).Name() == "Kind" {
+			field = f
+		}
+	}
+	if field < 0 {
+		return nil
+	}
+	t := types.Unalias(s.Field(field).Type())
+	if ptr, ok := t.(*types.Pointer); ok {
+		t = types.Unalias(ptr.Elem())
+	}
+	named, _ := t.(*types.Named)
+	return named
+}
+
+// optedOut returns true if the switch statement is marked with the nonExhaustive comment.
+func optedOut(pass *analysis.Pass, stack []ast.Node, sw *ast.SwitchStmt) bool {
+	file, ok := stack[0].(*ast.File)
+	return ok && hasDirective(pass, file, sw.Pos(), nonExhaustive)
+}
+
+// hasDirective returns true if a comment starting with directive is on pos's line or on the line
+// above it.
+func hasDirective(pass *analysis.Pass, file *ast.File, pos token.Pos, directive string) bool {
+	line := func(pos token.Pos) int { return pass.Fset.Position(pos).Line }
+	posLine := line(pos)
+	for _, cg := range file.Comments {
+		for _, c := range cg.List {
+			if l := line(c.Pos()); (l == posLine || l == posLine-1) && strings.HasPrefix(c.Text, directive) {
+				return true
+			}
+		}
+	}
+	return false
+}
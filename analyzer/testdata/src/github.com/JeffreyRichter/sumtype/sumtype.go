@@ -0,0 +1,4 @@
+// Package sumtype is a stub of github.com/JeffreyRichter/sumtype for analyzer tests.
+package sumtype
+
+type Caster[Json any] struct{}
@@ -0,0 +1,84 @@
+package layout
+
+import "github.com/JeffreyRichter/sumtype"
+
+type (
+	ShapeKind string
+
+	shape struct {
+		shapeCaster
+		Color  *string
+		Kind   *ShapeKind
+		Radius *int
+		Width  *int
+		Height *int
+	}
+
+	Shape struct {
+		shapeCaster
+		Color *string
+		Kind  *ShapeKind
+		_     *int
+		_     *int
+		_     *int
+	}
+
+	CircleShape struct {
+		shapeCaster
+		Color  *string
+		Kind   *ShapeKind
+		Radius *int
+		_      *int
+		_      *int
+	}
+
+	// Never passed to ValidateStructFields, but still checked.
+	MissingFieldShape struct { // want `MissingFieldShape is not a valid projection of shape: has 5 fields instead of 6`
+		shapeCaster
+		Color  *string
+		Kind   *ShapeKind
+		Radius *int
+		_      *int
+	}
+
+	WrongTypeShape struct { // want `WrongTypeShape is not a valid projection of shape: field #3 Radius \(\*float64\) has a different type than Radius \(\*int\)`
+		shapeCaster
+		Color  *string
+		Kind   *ShapeKind
+		Radius *float64
+		_      *int
+		_      *int
+	}
+
+	SwappedShape struct { // want `SwappedShape is not a valid projection of shape: field #4 Height must be named Width`
+		shapeCaster
+		Color  *string
+		Kind   *ShapeKind
+		_      *int
+		Height *int
+		Width  *int
+	}
+
+	//sumtype:invalid (a deliberately invalid projection isn't reported)
+	OptedOutShape struct {
+		shapeCaster
+		Color *string
+	}
+
+	shapeCaster sumtype.Caster[shape]
+
+	// Projections may also use sumtype.Caster directly as the first field.
+	DirectShape struct { // want `DirectShape is not a valid projection of shape: field #2 Kind \(string\) has a different type than Kind \(\*ShapeKind\)`
+		sumtype.Caster[shape]
+		Color *string
+		Kind  string
+		_     *int
+		_     *int
+		_     *int
+	}
+
+	// Not a projection: the first field isn't a caster.
+	unrelated struct {
+		Color *string
+	}
+)
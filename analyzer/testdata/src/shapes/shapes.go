@@ -0,0 +1,50 @@
+package shapes
+
+import "github.com/JeffreyRichter/sumtype"
+
+const (
+	CircleShapeKind    ShapeKind = "circle"
+	RectangleShapeKind ShapeKind = "rectangle"
+	PointShapeKind     ShapeKind = "point"
+)
+
+type (
+	ShapeKind string // want ShapeKind:`kinds.*CircleShapeKind.*PointShapeKind.*RectangleShapeKind`
+
+	shape struct {
+		shapeCaster
+		Color *string
+		Kind  *ShapeKind
+	}
+
+	Shape struct {
+		shapeCaster
+		Color *string
+		Kind  *ShapeKind
+	}
+
+	shapeCaster sumtype.Caster[shape]
+
+	// Color isn't a discriminator so switches on it aren't checked.
+	Color string
+)
+
+const Red Color = "red"
+
+func describe(s *Shape) string {
+	switch *s.Kind { // want `switch on ShapeKind is missing cases for PointShapeKind`
+	case CircleShapeKind:
+		return "circle"
+	case RectangleShapeKind:
+		return "rectangle"
+	default:
+		return "unknown"
+	}
+}
+
+func color(c Color) string {
+	switch c {
+	default:
+		return string(c)
+	}
+}
@@ -0,0 +1,33 @@
+package tagged
+
+import "github.com/JeffreyRichter/sumtype"
+
+type (
+	AnimalKind int // want AnimalKind:`kinds.*Bird 1.*Dog 0`
+	Legs       int
+
+	// The tagged field is the discriminator even though another field is named Kind.
+	animal struct {
+		sumtype.Caster[animal]
+		Kind    Legs
+		Species AnimalKind `json:"species" sumtype:"discriminator"`
+	}
+)
+
+const (
+	Dog AnimalKind = iota
+	Bird
+	Two Legs = 2
+)
+
+func sound(a *animal) string {
+	switch a.Species { // want `switch on AnimalKind is missing cases for Bird`
+	case Dog:
+		return "woof"
+	}
+	switch a.Kind {
+	case Two:
+		return "tweet"
+	}
+	return ""
+}
@@ -0,0 +1,35 @@
+package useshapes
+
+import "shapes"
+
+func all(k shapes.ShapeKind) int {
+	switch k {
+	case shapes.CircleShapeKind, shapes.RectangleShapeKind:
+		return 1
+	case shapes.PointShapeKind:
+		return 2
+	}
+	return 0
+}
+
+func missing(k shapes.ShapeKind) int {
+	switch k { // want `switch on shapes.ShapeKind is missing cases for PointShapeKind, RectangleShapeKind`
+	case shapes.CircleShapeKind:
+		return 1
+	default:
+		return 0
+	}
+}
+
+func optOut(k shapes.ShapeKind) int {
+	//sumtype:nonexhaustive
+	switch k {
+	case shapes.CircleShapeKind:
+		return 1
+	}
+	switch k { //sumtype:nonexhaustive (only circles are special)
+	case shapes.CircleShapeKind:
+		return 1
+	}
+	return 0
+}
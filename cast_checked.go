@@ -0,0 +1,6 @@
+//go:build sumtype_checked
+
+package sumtype
+
+// checkedCasts makes Cast panic on casts to projections that weren't validated.
+const checkedCasts = true
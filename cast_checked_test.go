@@ -0,0 +1,49 @@
+//go:build sumtype_checked
+
+package sumtype_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/JeffreyRichter/sumtype"
+)
+
+// TestCheckedCast tests that, with the sumtype_checked build tag, Cast panics when casting to a
+// projection that wasn't validated
+func TestCheckedCast(t *testing.T) {
+	// unvalidatedShape has shape's layout but was never passed to ValidateStructFields or Register
+	type unvalidatedShape struct {
+		shapeCaster
+		Color  *string
+		Kind   *ShapeKind
+		Radius *int
+		_      *int
+		_      *int
+	}
+	//sumtype:invalid
+	type smallShape struct {
+		shapeCaster
+		Color *string
+	}
+
+	c := (&Shape{}).SetCircle()
+	if sumtype.Cast[CircleShape](c.caster()) != c {
+		t.Error("Casting to a validated projection failed")
+	}
+	_ = c.caster().Json() // Casting to Json itself is always allowed
+
+	castPanic := func(cast func()) (msg string) {
+		defer func() { msg, _ = recover().(string) }()
+		cast()
+		return ""
+	}
+	msg := castPanic(func() { sumtype.Cast[unvalidatedShape](c.caster()) })
+	if !strings.Contains(msg, "unvalidatedShape which ValidateStructFields or Register didn't validate") {
+		t.Errorf("Unexpected panic for an unvalidated cast: %q", msg)
+	}
+	msg = castPanic(func() { sumtype.Cast[smallShape](c.caster()) })
+	if !strings.Contains(msg, "structs have different number of fields") {
+		t.Errorf("Unexpected panic for an incompatible cast: %q", msg)
+	}
+}
@@ -0,0 +1,7 @@
+//go:build !sumtype_checked
+
+package sumtype
+
+// checkedCasts makes Cast panic on casts to projections that weren't validated; build with the
+// sumtype_checked tag to enable it.
+const checkedCasts = false
@@ -0,0 +1,32 @@
+package sumtype
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"sync"
+)
+
+// validatedCasts contains the [2]reflect.Type{Json, projection} pairs that validateStructFields
+// found compatible; Cast checks them when built with the sumtype_checked build tag.
+var validatedCasts sync.Map
+
+// validatedCast returns true if projection is json itself or a projection validated for json.
+func validatedCast(json, projection reflect.Type) bool {
+	_, ok := validatedCasts.Load([2]reflect.Type{json, projection})
+	return json == projection || ok
+}
+
+// checkCast panics unless To is Json itself or a projection validated for Json. The panic message
+// includes the layout problems that make To incompatible with Json, if any.
+func checkCast[To, Json any]() {
+	json, projection := reflect.TypeFor[Json](), reflect.TypeFor[To]()
+	if validatedCast(json, projection) {
+		return
+	}
+	msg := fmt.Sprintf("sumtype: Cast from %s to %s which ValidateStructFields or Register didn't validate", json, projection)
+	if err := errors.Join(Caster[Json]{}.validateProjection(json, projection)...); err != nil {
+		msg += ": " + err.Error()
+	}
+	panic(msg)
+}
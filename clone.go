@@ -0,0 +1,187 @@
+package sumtype
+
+import (
+	"reflect"
+	"sync"
+	"unsafe"
+)
+
+// cloner deep copies src into dst which already holds a shallow copy of src; it replaces the
+// pointers, slices, maps, and interfaces reachable through exported fields with copies. copies
+// maps the pointers, slices, and maps already copied to their copies.
+type cloner func(dst, src reflect.Value, copies map[cloneKey]reflect.Value)
+
+// cloneKey identifies a pointer, slice, or map being copied.
+type cloneKey struct {
+	ptr uintptr      // ptr is the address of the referenced value
+	typ reflect.Type // typ is the reference's type
+	len int          // len is a slice's length (slices sharing an array may differ in length)
+}
+
+// copied returns the copy of the reference src if it was already made so cyclic and shared
+// references are copied once. Otherwise it returns a key to record the copy under.
+func copied(src reflect.Value, copies map[cloneKey]reflect.Value) (reflect.Value, cloneKey, bool) {
+	key := cloneKey{src.Pointer(), src.Type(), 0}
+	if src.Kind() == reflect.Slice {
+		key.len = src.Len()
+	}
+	c, ok := copies[key]
+	return c, key, ok
+}
+
+// cloners maps a reflect.Type to its cloner (or a nil cloner if a shallow copy suffices).
+var cloners sync.Map
+
+// Clone returns a deep copy of the Json struct instance: the values referenced by pointer,
+// slice, map, and interface fields (including fields irrelevant to the current kind) are copied
+// so modifying the copy, or changing its kind, never affects the original. Unexported fields of
+// nested structs (like time.Time's location) are copied shallowly. References shared by the
+// original, including cyclic ones, are shared by the copy. The copying plan for each type is
+// computed once and cached.
+func (c *Caster[Json]) Clone() *Json {
+	clone := new(Json)
+	*clone = *c.Json()
+	if cl := clonerFor(reflect.TypeFor[Json]()); cl != nil {
+		cl(reflect.ValueOf(clone).Elem(), reflect.ValueOf(c.Json()).Elem(), map[cloneKey]reflect.Value{})
+	}
+	return clone
+}
+
+// CloneAs deep copies the Json struct instance (see Clone) and casts the copy To another sum
+// type projection type. Use it to fork a record before converting its kind.
+func CloneAs[To any, Json any](caster *Caster[Json]) *To {
+	return Cast[To]((*Caster[Json])(unsafe.Pointer(caster.Clone())))
+}
+
+// clonerFor returns t's cached cloner, building it if necessary.
+func clonerFor(t reflect.Type) cloner {
+	if cl, ok := cloners.Load(t); ok {
+		return cl.(cloner)
+	}
+	actual, _ := cloners.LoadOrStore(t, buildCloner(t, map[reflect.Type]*cloner{}))
+	return actual.(cloner)
+}
+
+// buildCloner returns t's cloner or nil if a shallow copy of a t suffices. building contains the
+// types whose cloners are being built so recursive types refer to their own cloner.
+func buildCloner(t reflect.Type, building map[reflect.Type]*cloner) cloner {
+	if cl, ok := building[t]; ok {
+		return func(dst, src reflect.Value, copies map[cloneKey]reflect.Value) { (*cl)(dst, src, copies) }
+	}
+	cl := new(cloner)
+	building[t] = cl
+	defer delete(building, t)
+
+	switch t.Kind() {
+	case reflect.Pointer:
+		elem := buildCloner(t.Elem(), building)
+		*cl = func(dst, src reflect.Value, copies map[cloneKey]reflect.Value) {
+			if src.IsNil() {
+				return
+			}
+			p, key, ok := copied(src, copies)
+			if !ok {
+				p = reflect.New(t.Elem())
+				copies[key] = p
+				p.Elem().Set(src.Elem())
+				if elem != nil {
+					elem(p.Elem(), src.Elem(), copies)
+				}
+			}
+			dst.Set(p)
+		}
+
+	case reflect.Slice:
+		elem := buildCloner(t.Elem(), building)
+		*cl = func(dst, src reflect.Value, copies map[cloneKey]reflect.Value) {
+			if src.IsNil() {
+				return
+			}
+			s, key, ok := copied(src, copies)
+			if !ok {
+				s = reflect.MakeSlice(t, src.Len(), src.Len())
+				copies[key] = s
+				reflect.Copy(s, src)
+				if elem != nil {
+					for i := range src.Len() {
+						elem(s.Index(i), src.Index(i), copies)
+					}
+				}
+			}
+			dst.Set(s)
+		}
+
+	case reflect.Array:
+		elem := buildCloner(t.Elem(), building)
+		if elem == nil {
+			return nil
+		}
+		*cl = func(dst, src reflect.Value, copies map[cloneKey]reflect.Value) {
+			for i := range src.Len() {
+				elem(dst.Index(i), src.Index(i), copies)
+			}
+		}
+
+	case reflect.Map:
+		key, elem := buildCloner(t.Key(), building), buildCloner(t.Elem(), building)
+		*cl = func(dst, src reflect.Value, copies map[cloneKey]reflect.Value) {
+			if src.IsNil() {
+				return
+			}
+			m, mapKey, ok := copied(src, copies)
+			if !ok {
+				m = reflect.MakeMapWithSize(t, src.Len())
+				copies[mapKey] = m
+				for k, v := range src.Seq2() {
+					m.SetMapIndex(cloneValue(key, k, copies), cloneValue(elem, v, copies))
+				}
+			}
+			dst.Set(m)
+		}
+
+	case reflect.Interface:
+		*cl = func(dst, src reflect.Value, copies map[cloneKey]reflect.Value) {
+			if src.IsNil() {
+				return
+			}
+			dst.Set(cloneValue(clonerFor(src.Elem().Type()), src.Elem(), copies))
+		}
+
+	case reflect.Struct:
+		type fieldCloner struct {
+			index  int
+			cloner cloner
+		}
+		var fields []fieldCloner
+		for f := range t.NumField() {
+			if !t.Field(f).IsExported() {
+				continue
+			}
+			if fc := buildCloner(t.Field(f).Type, building); fc != nil {
+				fields = append(fields, fieldCloner{f, fc})
+			}
+		}
+		if fields == nil {
+			return nil
+		}
+		*cl = func(dst, src reflect.Value, copies map[cloneKey]reflect.Value) {
+			for _, f := range fields {
+				f.cloner(dst.Field(f.index), src.Field(f.index), copies)
+			}
+		}
+
+	default: // Strings are immutable; channels, functions, and unsafe pointers are shared
+		return nil
+	}
+	return *cl
+}
+
+// cloneValue returns a settable deep copy of v made with cl (which may be nil).
+func cloneValue(cl cloner, v reflect.Value, copies map[cloneKey]reflect.Value) reflect.Value {
+	clone := reflect.New(v.Type()).Elem()
+	clone.Set(v)
+	if cl != nil {
+		cl(clone, v, copies)
+	}
+	return clone
+}
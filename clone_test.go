@@ -0,0 +1,84 @@
+package sumtype_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/JeffreyRichter/sumtype"
+)
+
+// TestClone tests that a cloned shape shares no storage with the original
+func TestClone(t *testing.T) {
+	c := (&Shape{}).SetCircle()
+	c.Color, c.Radius = ptr("red"), ptr(1)
+
+	clone := sumtype.CloneAs[CircleShape](c.caster())
+	*clone.Color, *clone.Radius = "blue", 2
+	r := clone.SetRectangle()
+	r.Width, r.Height = ptr(3), ptr(4)
+	if *c.Color != "red" || *c.Radius != 1 || *c.Kind != CircleShapeKind {
+		t.Errorf("Modifying the clone modified the original: %v", c)
+	}
+	if *r.Color != "blue" || *r.Kind != RectangleShapeKind {
+		t.Errorf("Unexpected clone: %v", r)
+	}
+}
+
+type (
+	// tree is a sum type with recursive, slice, map, array, and interface fields to clone
+	tree struct {
+		treeCaster
+		Kind     string
+		Children []*tree
+		Labels   map[string][]string
+		Pair     [2]*int
+		Any      any
+		private  *int
+	}
+
+	treeCaster sumtype.Caster[tree]
+)
+
+// TestCloneDeep tests that Clone copies every kind of reference reachable through exported fields
+func TestCloneDeep(t *testing.T) {
+	private := ptr(7)
+	orig := &tree{Kind: "node", Labels: map[string][]string{"a": {"x"}}, Pair: [2]*int{ptr(1), nil},
+		Any: map[string]any{"n": []any{1.0}}, private: private}
+	orig.Children = []*tree{{Kind: "leaf", Labels: map[string][]string{"b": {"y"}}}, nil}
+	want := &tree{Kind: "node", Labels: map[string][]string{"a": {"x"}}, Pair: [2]*int{ptr(1), nil},
+		Any: map[string]any{"n": []any{1.0}}, private: private}
+	want.Children = []*tree{{Kind: "leaf", Labels: map[string][]string{"b": {"y"}}}, nil}
+
+	for range 2 { // The 2nd time uses the cached plan
+		clone := (*sumtype.Caster[tree])(&orig.treeCaster).Clone()
+		if !reflect.DeepEqual(clone, orig) {
+			t.Fatalf("Clone differs from the original: %+v", clone)
+		}
+		clone.Children[0].Labels["b"][0] = "changed"
+		clone.Labels["a"] = append(clone.Labels["a"], "more")
+		*clone.Pair[0] = 9
+		clone.Any.(map[string]any)["n"].([]any)[0] = 2.0
+		if !reflect.DeepEqual(orig, want) {
+			t.Fatalf("Modifying the clone modified the original: %+v", orig)
+		}
+		if clone.private != private {
+			t.Error("Unexported fields should be copied shallowly")
+		}
+	}
+}
+
+// TestCloneCycles tests that Clone copies cyclic and shared references once
+func TestCloneCycles(t *testing.T) {
+	orig := &tree{Kind: "node", Labels: map[string][]string{"a": {"x"}}}
+	orig.Children = []*tree{orig, orig}
+	orig.Any = orig.Labels
+
+	clone := (*sumtype.Caster[tree])(&orig.treeCaster).Clone()
+	if clone.Children[0] == orig || clone.Children[0] != clone.Children[1] || clone.Children[0].Children[0] != clone.Children[0] {
+		t.Errorf("Expected the copied cycle to refer to the copy, got %+v", clone)
+	}
+	clone.Labels["b"] = nil
+	if _, ok := clone.Any.(map[string][]string)["b"]; !ok || len(orig.Labels) != 1 {
+		t.Error("Expected the copy to share its copied map and not the original's")
+	}
+}
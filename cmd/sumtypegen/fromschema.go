@@ -0,0 +1,359 @@
+package main
+
+import (
+	"encoding/json/v2"
+	"fmt"
+	"go/parser"
+	"go/token"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/JeffreyRichter/sumtype"
+)
+
+// schemaFile is the part of an OpenAPI 3.1 document or JSON Schema holding named schemas.
+type schemaFile struct {
+	Defs       map[string]*sumtype.Schema `json:"$defs"`
+	Components struct {
+		Schemas map[string]*sumtype.Schema `json:"schemas"`
+	} `json:"components"`
+}
+
+// variant is one kind's schema with the properties of its allOf schemas and references merged.
+type variant struct {
+	ref        string                     // ref is the $ref to the kind's schema or "" if it's inline
+	properties map[string]*sumtype.Schema // properties are the kind's properties by JSON name
+}
+
+// loadSchemaSumType reads the OpenAPI 3.1 document or JSON Schema in path and returns the sum type
+// for its discriminated oneOf schema named common (or the document's root schema if there's no
+// such named schema). The Json struct is named typeName and belongs to dir's package.
+func loadSchemaSumType(dir, path, typeName, common string) (*sumType, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var root sumtype.Schema
+	var file schemaFile
+	if err := json.Unmarshal(data, &root); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	if err := json.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	pkg, err := packageName(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	if common == "" {
+		common = upperFirst(typeName)
+	}
+	st := &sumType{Package: pkg, Json: typeName, Common: common, Caster: lowerFirst(typeName) + "Caster",
+		KindType: common + "Kind", KindIsPtr: true, DeclareKindType: true, DeclareJson: true}
+	if st.Common == st.Json {
+		return nil, fmt.Errorf("type %s must be unexported (or use -common to name the common projection)", typeName)
+	}
+	sum := file.Components.Schemas[common]
+	if sum == nil {
+		sum = file.Defs[common]
+	}
+	if sum == nil {
+		sum = &root
+	}
+	if err := st.fromSchema(sum, &file); err != nil {
+		return nil, fmt.Errorf("%s: schema %s: %w", path, common, err)
+	}
+	return st, nil
+}
+
+// fromSchema sets st's discriminator, kinds, and fields from the discriminated oneOf schema sum.
+func (st *sumType) fromSchema(sum *sumtype.Schema, file *schemaFile) error {
+	variants, err := file.variants(sum)
+	if err != nil {
+		return err
+	}
+	if len(variants) == 0 {
+		return fmt.Errorf("schema has no oneOf schemas or discriminator mapping")
+	}
+	kindName, err := discriminatorName(sum, variants)
+	if err != nil {
+		return err
+	}
+
+	// Determine each variant's kind: its discriminator mapping key, its discriminator's const, or
+	// (like OpenAPI's implicit mapping) its referenced schema's name
+	values := []string{}
+	for _, v := range variants {
+		value := ""
+		if sum.Discriminator != nil {
+			for _, key := range slices.Sorted(maps.Keys(sum.Discriminator.Mapping)) {
+				if v.ref != "" && sum.Discriminator.Mapping[key] == v.ref {
+					value = key
+					break
+				}
+			}
+		}
+		if p := v.properties[kindName]; value == "" && p != nil {
+			value, _ = p.Const.(string)
+		}
+		if value == "" && v.ref != "" {
+			value = v.ref[strings.LastIndex(v.ref, "/")+1:]
+		}
+		if value == "" || slices.Contains(values, value) {
+			return fmt.Errorf("can't determine a unique kind for oneOf schema %d", len(values))
+		}
+		values = append(values, value)
+		st.addKind(value)
+	}
+	for _, k := range st.Kinds {
+		if !token.IsIdentifier(k.Const) {
+			return fmt.Errorf("kind %q doesn't produce a Go identifier", k.Value)
+		}
+	}
+
+	// Shared properties are the sum schema's own properties and those every kind has; the others
+	// belong to the kinds that have them
+	shared, _ := file.flatten(sum, map[*sumtype.Schema]bool{})
+	kinds := map[string][]string{kindName: nil}
+	for i, v := range variants {
+		for name := range v.properties {
+			kinds[name] = append(kinds[name], values[i])
+		}
+	}
+	names := []string{}
+	for _, name := range slices.Sorted(maps.Keys(kinds)) {
+		if shared[name] != nil || len(kinds[name]) == len(variants) || name == kindName {
+			names, kinds[name] = append(names, name), nil
+		}
+	}
+	for _, k := range st.Kinds {
+		for _, name := range slices.Sorted(maps.Keys(kinds)) {
+			if slices.Contains(names, name) || kinds[name][0] != k.Value {
+				continue
+			}
+			names = append(names, name)
+		}
+	}
+
+	goNames := map[string]string{}
+	for _, name := range names {
+		f := field{Name: goName(name), Kinds: kinds[name], jsonName: name}
+		if !token.IsExported(f.Name) || !token.IsIdentifier(f.Name) {
+			return fmt.Errorf("property %q doesn't produce an exported Go identifier", name)
+		}
+		if other, dup := goNames[f.Name]; dup {
+			return fmt.Errorf("properties %q and %q are both named %s in Go", other, name, f.Name)
+		}
+		goNames[f.Name] = name
+
+		if name == kindName {
+			st.KindField = f.Name
+			f.Type = "*" + st.KindType
+			f.Tag = fmt.Sprintf("`json:\"%s,omitempty\" sumtype:\"discriminator,kinds=%s\"`", name, strings.Join(values, "|"))
+			f.Doc = []string{fmt.Sprintf(" %s is the discriminator indicating which type of %s", f.Name, st.Common)}
+			st.Fields = append(st.Fields, f)
+			continue
+		}
+
+		// Every schema for the property must map to the same Go type
+		schemas := []*sumtype.Schema{shared[name]}
+		for _, v := range variants {
+			schemas = append(schemas, v.properties[name])
+		}
+		for _, s := range schemas {
+			if s == nil {
+				continue
+			}
+			typ := st.goType(s, file, map[*sumtype.Schema]bool{})
+			if f.Type != "" && f.Type != typ {
+				return fmt.Errorf("property %q is both %s and %s", name, f.Type, typ)
+			}
+			if f.Type = typ; f.Doc == nil && s.Description != "" {
+				f.Doc = descriptionDoc(f.Name, s.Description)
+			}
+		}
+		if f.Type[0] != '[' && !strings.HasPrefix(f.Type, "map[") && f.Type != "jsontext.Value" {
+			f.Type = "*" + f.Type // Scalars are pointers so omitted members are distinguishable from zero values
+		}
+		if f.Tag = fmt.Sprintf("`json:\"%s,omitempty\"`", name); f.Kinds != nil {
+			f.Tag = fmt.Sprintf("`json:\"%s,omitempty\" sumtype:\"kind=%s\"`", name, strings.Join(f.Kinds, "|"))
+		}
+		if f.Doc == nil && f.Kinds == nil {
+			f.Doc = []string{fmt.Sprintf(" %s is shared by all %s kinds", f.Name, st.Common)}
+		} else if f.Doc == nil {
+			f.Doc = []string{fmt.Sprintf(" %s is used by %s kinds", f.Name, strings.Join(f.Kinds, " and "))}
+		}
+		st.Fields = append(st.Fields, f)
+	}
+	return nil
+}
+
+// variants returns sum's oneOf schemas or, if it has none, the schemas of its discriminator mapping.
+func (file *schemaFile) variants(sum *sumtype.Schema) ([]variant, error) {
+	schemas := sum.OneOf
+	if len(schemas) == 0 && sum.Discriminator != nil {
+		for _, key := range slices.Sorted(maps.Keys(sum.Discriminator.Mapping)) {
+			schemas = append(schemas, &sumtype.Schema{Ref: sum.Discriminator.Mapping[key]})
+		}
+	}
+	variants := []variant{}
+	for _, s := range schemas {
+		v := variant{ref: s.Ref}
+		var err error
+		if v.properties, err = file.flatten(s, map[*sumtype.Schema]bool{sum: true}); err != nil {
+			return nil, err
+		}
+		variants = append(variants, v)
+	}
+	return variants, nil
+}
+
+// flatten returns the properties of schema s including those of its allOf schemas and references.
+// It doesn't descend into the schemas in seen, preventing infinite recursion.
+func (file *schemaFile) flatten(s *sumtype.Schema, seen map[*sumtype.Schema]bool) (map[string]*sumtype.Schema, error) {
+	properties := map[string]*sumtype.Schema{}
+	if seen[s] {
+		// The sum schema's own properties are shared by all variants referencing it
+		maps.Copy(properties, s.Properties)
+		return properties, nil
+	}
+	seen[s] = true
+	if s.Ref != "" {
+		target, err := file.resolve(s.Ref)
+		if err != nil {
+			return nil, err
+		}
+		return file.flatten(target, seen)
+	}
+	maps.Copy(properties, s.Properties)
+	for _, sub := range s.AllOf {
+		subProperties, err := file.flatten(sub, seen)
+		if err != nil {
+			return nil, err
+		}
+		maps.Copy(properties, subProperties)
+	}
+	return properties, nil
+}
+
+// resolve returns the schema referenced by ref, which must refer to a named component or $defs schema.
+func (file *schemaFile) resolve(ref string) (*sumtype.Schema, error) {
+	for prefix, schemas := range map[string]map[string]*sumtype.Schema{
+		"#/components/schemas/": file.Components.Schemas, "#/$defs/": file.Defs} {
+		if name, ok := strings.CutPrefix(ref, prefix); ok {
+			name = strings.NewReplacer("~1", "/", "~0", "~").Replace(name)
+			if s := schemas[name]; s != nil {
+				return s, nil
+			}
+		}
+	}
+	return nil, fmt.Errorf("can't resolve $ref %q", ref)
+}
+
+// discriminatorName returns the discriminator property's JSON name: the OpenAPI discriminator's
+// propertyName or else the property that's a const in all variants.
+func discriminatorName(sum *sumtype.Schema, variants []variant) (string, error) {
+	if sum.Discriminator != nil && sum.Discriminator.PropertyName != "" {
+		return sum.Discriminator.PropertyName, nil
+	}
+	for _, name := range slices.Sorted(maps.Keys(variants[0].properties)) {
+		isConst := func(v variant) bool { return v.properties[name] != nil && v.properties[name].Const != nil }
+		if !slices.ContainsFunc(variants, func(v variant) bool { return !isConst(v) }) {
+			return name, nil
+		}
+	}
+	return "", fmt.Errorf("schema has no discriminator and no property that's a const in all oneOf schemas")
+}
+
+// goType returns the Go type (without a leading "*") for JSON values described by schema s and
+// adds any package it needs to st.Imports. Objects with declared properties become jsontext.Value.
+// visiting contains the referenced schemas being described to stop recursion on recursive schemas.
+func (st *sumType) goType(s *sumtype.Schema, file *schemaFile, visiting map[*sumtype.Schema]bool) string {
+	if s.Ref != "" {
+		target, err := file.resolve(s.Ref)
+		if err != nil || visiting[target] {
+			return "jsontext.Value"
+		}
+		visiting[target] = true
+		defer delete(visiting, target)
+		return st.goType(target, file, visiting)
+	}
+	switch s.Type {
+	case "string":
+		switch {
+		case s.Format == "date-time":
+			if !slices.Contains(st.Imports, "time") {
+				st.Imports = append(st.Imports, "time")
+			}
+			return "time.Time"
+		case s.ContentEncoding == "base64":
+			return "[]byte"
+		}
+		return "string"
+	case "integer":
+		if s.Format == "int32" || s.Format == "int64" {
+			return s.Format
+		}
+		return "int"
+	case "number":
+		return "float64"
+	case "boolean":
+		return "bool"
+	case "array":
+		if s.Items == nil {
+			return "[]jsontext.Value"
+		}
+		return "[]" + st.goType(s.Items, file, visiting)
+	case "object":
+		if s.AdditionalProperties != nil && len(s.Properties) == 0 {
+			return "map[string]" + st.goType(s.AdditionalProperties, file, visiting)
+		}
+	}
+	return "jsontext.Value" // Any JSON value
+}
+
+// descriptionDoc returns doc comment lines for the field named name from a schema description. A
+// description starting with an article (like "The pet's name.") becomes "Name is the pet's name.";
+// others are used as is.
+func descriptionDoc(name, description string) []string {
+	lines := strings.Split(strings.TrimSpace(description), "\n")
+	if article, _, _ := strings.Cut(lines[0], " "); slices.Contains([]string{"A", "An", "The"}, article) {
+		lines[0] = name + " is " + lowerFirst(lines[0])
+	}
+	doc := []string{}
+	for _, line := range lines {
+		doc = append(doc, " "+line)
+	}
+	return doc
+}
+
+// packageName returns the name of the package whose non-test Go files are in dir or, if there
+// are none, dir's base name.
+func packageName(dir string) (string, error) {
+	paths, err := filepath.Glob(filepath.Join(dir, "*.go"))
+	if err != nil {
+		return "", err
+	}
+	for _, path := range paths {
+		if strings.HasSuffix(path, "_test.go") {
+			continue
+		}
+		f, err := parser.ParseFile(token.NewFileSet(), path, nil, parser.PackageClauseOnly)
+		if err != nil {
+			return "", err
+		}
+		return f.Name.Name, nil
+	}
+	abs, err := filepath.Abs(dir)
+	if err != nil {
+		return "", err
+	}
+	if name := filepath.Base(abs); token.IsIdentifier(name) {
+		return name, nil
+	}
+	return "", fmt.Errorf("can't determine the package name for directory %s", dir)
+}
@@ -0,0 +1,164 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"text/template"
+)
+
+// generate returns the gofmt'd Go source for st's projections, caster type, and methods.
+func generate(st *sumType) ([]byte, error) {
+	var b bytes.Buffer
+	if err := sumTypeTemplate.Execute(&b, st); err != nil {
+		return nil, err
+	}
+	src, err := format.Source(b.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("formatting generated code: %w\n%s", err, b.Bytes())
+	}
+	return src, nil
+}
+
+var sumTypeTemplate = template.Must(template.New("sumtype").Parse(`// Code generated by sumtypegen; DO NOT EDIT.
+
+package {{.Package}}
+
+import (
+	"encoding/json/jsontext"
+	"encoding/json/v2"
+{{- range .Imports}}
+	"{{.}}"
+{{- end}}
+	"unsafe"
+
+	"github.com/JeffreyRichter/sumtype"
+)
+
+// At app initialization, panic if any of {{.Json}}'s projection structs don't match
+var _ = sumtype.Caster[{{.Json}}]{}.ValidateStructFields(true{{range .Projections}}, {{.Name}}{}{{end}})
+
+// At app initialization, register {{.Json}}'s discriminator field and the projection for each kind
+var _ = sumtype.Register[{{.Json}}](true, "{{.KindField}}", map[{{.KindType}}]any{
+{{- range .Kinds}}
+	{{.Const}}: {{.Projection}}{},
+{{- end}}
+})
+
+const (
+{{- range .Kinds}}
+	// {{.Const}} is the kind for {{.Value}} {{$.Json}}s
+	{{.Const}} {{$.KindType}} = {{printf "%q" .Value}}
+{{end -}}
+)
+
+type (
+{{- if .DeclareKindType}}
+	// {{.KindType}} is the discriminator indicating which type of {{.Common}}
+	{{.KindType}} string
+{{end}}
+{{- if .DeclareJson}}
+	// {{.Json}} is package-private and used for (un)marshaling (all data fields are public).
+	{{.Json}} struct {
+		// {{.Caster}} MUST be 1st field, unexported & embedded for method "inheritance"
+		{{.Caster}}
+{{range .Fields}}
+{{- range .Doc}}
+		//{{.}}
+{{- end}}
+		{{.Name}} {{.Type}} {{.Tag}}
+{{end -}}
+	}
+{{end}}
+{{- range .Projections}}
+	// {{.Doc}}
+	{{.Name}} struct {
+		// {{$.Caster}} MUST be 1st field, unexported & embedded for method "inheritance"
+		{{$.Caster}}
+{{range .Fields}}
+{{- range .Doc}}
+		//{{.}}
+{{- end}}
+		{{.Name}} {{.Type}}{{with .Tag}} {{.}}{{end}}
+{{end -}}
+	}
+{{end}}
+	// {{.Caster}} provides methods to cast between *{{.Json}} and its variants. The 1st field of {{.Json}}
+	// and all its variants is an unexported {{.Caster}} whose underlying type is sumtype.Caster[{{.Json}}].
+	// NOTE: This also hides sumtypes.Caster's MarshalJSON[To]/UnmarshalJSON[From]/String methods so they
+	// cannot be called directly on {{.Json}} variants.
+	{{.Caster}} sumtype.Caster[{{.Json}}]
+)
+
+// Check at compile time that all {{.Json}} structures have the same size
+{{- range .Projections}}
+var _ [0]struct{} = [unsafe.Sizeof({{$.Json}}{}) - unsafe.Sizeof({{.Name}}{})]struct{}{}
+{{- end}}
+
+// RULES: String & MarshalJSON[To] require by-val receiver, UnmarshalJSON[From] requires by-ref receiver
+{{range .Projections}}
+// String returns a readable JSON representation of the {{.Name}}
+func (s {{.Name}}) String() string { return (&s).caster().String() }
+
+// MarshalJSON marshals the {{.Name}} to JSON
+func (s {{.Name}}) MarshalJSON() ([]byte, error) { return (&s).caster().MarshalJSON() }
+
+// UnmarshalJSON unmarshals JSON data to the {{.Name}}
+func (s *{{.Name}}) UnmarshalJSON(data []byte) error { return s.caster().UnmarshalJSON(data) }
+
+// MarshalJSONTo marshals the {{.Name}} to enc
+func (s {{.Name}}) MarshalJSONTo(enc *jsontext.Encoder) error { return (&s).caster().MarshalJSONTo(enc) }
+
+// UnmarshalJSONFrom unmarshals the {{.Name}} from dec
+func (s *{{.Name}}) UnmarshalJSONFrom(dec *jsontext.Decoder) error { return s.caster().UnmarshalJSONFrom(dec) }
+{{end}}
+// RULES: Methods that cast a pointer from 1 type to another, require by-ref receiver (XxxCaster methods).
+
+// caster returns {{.Caster}}'s underlying sumtype.Caster to access its helper methods.
+func (c *{{.Caster}}) caster() *sumtype.Caster[{{.Json}}] { return (*sumtype.Caster[{{.Json}}])(c) }
+
+// json casts the pointer *c to *{{.Json}}, the JSONable type (ALL JSON fields are public).
+func (c *{{.Caster}}) json() *{{.Json}} { return c.caster().Json() }
+
+// ensureKind ensures that the current {{.Json}} kind matches the specified kind; it panics if not.
+func (c *{{.Caster}}) ensureKind(kind {{.KindType}}) {
+	switch {
+{{- if .KindIsPtr}}
+	case c.json().{{.KindField}} == nil:
+		panic(sumtype.ErrNilKind)
+	case *c.json().{{.KindField}} != kind:
+		panic(&sumtype.KindMismatchError{Have: *c.json().{{.KindField}}, Want: kind})
+{{- else}}
+	case c.json().{{.KindField}} != kind:
+		panic(&sumtype.KindMismatchError{Have: c.json().{{.KindField}}, Want: kind})
+{{- end}}
+	}
+}
+
+// {{.Common}} casts a *Xxx{{.Common}} to the common *{{.Common}}
+func (c *{{.Caster}}) {{.Common}}() *{{.Common}} { return sumtype.Cast[{{.Common}}](c.caster()) }
+{{range .Kinds}}
+// {{.Name}} casts any *Xxx{{$.Common}} to a *{{.Projection}}; it panics if {{$.KindField}} != {{.Const}}.
+func (c *{{$.Caster}}) {{.Name}}() *{{.Projection}} {
+	c.ensureKind({{.Const}})
+	return sumtype.Cast[{{.Projection}}](c.caster())
+}
+{{end}}
+{{- range .Kinds}}
+// Try{{.Name}} casts any *Xxx{{$.Common}} to a *{{.Projection}}; it returns an error if {{$.KindField}} != {{.Const}}.
+func (c *{{$.Caster}}) Try{{.Name}}() (*{{.Projection}}, error) {
+	return sumtype.TryCast[{{.Projection}}](c.caster(), {{.Const}})
+}
+{{end}}
+{{- range .Kinds}}
+// Set{{.Name}} changes any *Xxx{{$.Common}}'s kind to {{.Value}}, zeroing the fields irrelevant to it, and casts it to a *{{.Projection}}
+func (c *{{$.Caster}}) Set{{.Name}}() *{{.Projection}} {
+	return sumtype.MustSetKind[{{.Projection}}](c.caster(), {{.Const}})
+}
+{{end}}
+// String returns a readable JSON representation of the {{.Json}}
+func (c *{{.Caster}}) String() string {
+	j, _ := json.Marshal(c.json(), jsontext.WithIndent("  "))
+	return string(j)
+}
+`))
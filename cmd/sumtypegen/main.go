@@ -0,0 +1,120 @@
+// Sumtypegen generates the projection structs, xxxCaster type, and cast/set methods for a
+// sum type built on sumtype.Caster from a single annotated, package-private Json struct.
+//
+// Given a struct like this in package shapes:
+//
+//	//go:generate go run github.com/JeffreyRichter/sumtype/cmd/sumtypegen -type=shape
+//	type shape struct {
+//		shapeCaster
+//		Color  *string    `json:"color,omitempty"`
+//		Kind   *ShapeKind `json:"kind,omitempty" sumtype:"discriminator"`
+//		Radius *int       `json:"radius,omitempty" sumtype:"kind=circle"`
+//		Width  *int       `json:"width,omitempty" sumtype:"kind=rectangle"`
+//		Height *int       `json:"height,omitempty" sumtype:"kind=rectangle"`
+//	}
+//
+// running "go generate" creates shape_sumtype.go containing the ShapeKind constants, the Shape,
+// CircleShape, and RectangleShape projections, the shapeCaster type and all of its methods.
+//
+// Fields without a sumtype tag are shared by all kinds. A field tagged `sumtype:"kind=a|b"` is
+// exposed only by the projections of kinds a and b. Exactly one field must be tagged
+// `sumtype:"discriminator"`; its tag may also list kinds that have no kind-specific fields
+// with `sumtype:"discriminator,kinds=a|b"`. If the discriminator's type is not declared in the
+// package, sumtypegen declares it as a string type. The Caster.Validate constraints "required",
+// "min=N" and "max=N" may follow a field's kinds (ex: `sumtype:"kind=circle,required,min=0"`);
+// they're copied to the sumtype tags of the projections exposing the field.
+//
+// With -schema, sumtypegen instead reads the sum type from an OpenAPI 3.1 document or JSON Schema
+// file and also generates the Json struct. The sum type's schema is the components.schemas (or
+// $defs) entry named by -common (the -type name with its first letter uppercased by default) or
+// else the file's root schema. It must be a oneOf (or an OpenAPI schema with a discriminator
+// mapping) whose kinds are named by the discriminator mapping, by a const discriminator property,
+// or by the referenced schemas' names. Properties all kinds have are shared; the others are
+// exposed only by the kinds having them.
+//
+// With -openapi, sumtypegen instead writes <type>_openapi.json containing the OpenAPI 3.1
+// components.schemas entries for the sum type: the common projection's schema with a
+// discriminator mapping and an allOf schema per kind (see sumtype.OpenAPISchemas).
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+var (
+	typeNames = flag.String("type", "", "comma-separated list of Json struct names; must be set")
+	common    = flag.String("common", "", "name of the common projection; default is the exported Json struct name")
+	output    = flag.String("output", "", "output file name; default srcdir/<type>_sumtype.go")
+	schema    = flag.String("schema", "", "read the sum type from this OpenAPI 3.1 or JSON Schema file and generate its Json struct too")
+	openapi   = flag.Bool("openapi", false, "write OpenAPI 3.1 components.schemas to srcdir/<type>_openapi.json instead of Go code")
+)
+
+func usage() {
+	fmt.Fprintf(os.Stderr, "Usage of sumtypegen:\n")
+	fmt.Fprintf(os.Stderr, "\tsumtypegen -type T [directory]\n")
+	fmt.Fprintf(os.Stderr, "\tsumtypegen -type T -schema file.json [directory]\n")
+	fmt.Fprintf(os.Stderr, "\tsumtypegen -type T -openapi [directory]\n")
+	fmt.Fprintf(os.Stderr, "Flags:\n")
+	flag.PrintDefaults()
+}
+
+func main() {
+	log.SetFlags(0)
+	log.SetPrefix("sumtypegen: ")
+	flag.Usage = usage
+	flag.Parse()
+	if *typeNames == "" || flag.NArg() > 1 {
+		flag.Usage()
+		os.Exit(2)
+	}
+	names := strings.Split(*typeNames, ",")
+	if len(names) > 1 && (*common != "" || *output != "") {
+		log.Fatal("-common and -output require a single -type")
+	}
+
+	dir := "."
+	if flag.NArg() == 1 {
+		dir = flag.Arg(0)
+	}
+	for _, name := range names {
+		if err := run(dir, name, *common, *output, *schema, *openapi); err != nil {
+			log.Fatal(err)
+		}
+	}
+}
+
+// run generates the Go (or OpenAPI) file for the Json struct named typeName found in dir (or
+// described by schemaFile).
+func run(dir, typeName, common, output, schemaFile string, openapi bool) error {
+	st, srcFile, err := (*sumType)(nil), "", error(nil)
+	if schemaFile != "" {
+		st, err = loadSchemaSumType(dir, schemaFile, typeName, common)
+	} else {
+		st, srcFile, err = loadSumType(dir, typeName, common)
+	}
+	if err != nil {
+		return err
+	}
+	src, suffix := []byte(nil), "_sumtype.go"
+	if openapi {
+		src, err = openAPI(st)
+		suffix = "_openapi.json"
+	} else {
+		src, err = generate(st)
+		if strings.HasSuffix(srcFile, "_test.go") {
+			suffix = "_sumtype_test.go" // Keep test-only sum types in test files
+		}
+	}
+	if err != nil {
+		return err
+	}
+	if output == "" {
+		output = filepath.Join(dir, strings.ToLower(typeName)+suffix)
+	}
+	return os.WriteFile(output, src, 0o644)
+}
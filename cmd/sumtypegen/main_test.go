@@ -0,0 +1,236 @@
+package main
+
+import (
+	"bytes"
+	"flag"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+var update = flag.Bool("update", false, "update the golden files")
+
+// TestGenerateGolden tests that generating code for testdata/shape.go matches the golden file
+func TestGenerateGolden(t *testing.T) {
+	st, srcFile, err := loadSumType("testdata", "shape", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if filepath.Base(srcFile) != "shape.go" {
+		t.Errorf("Expected shape.go, got %s", srcFile)
+	}
+	src, err := generate(st)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	golden := filepath.Join("testdata", "shape_sumtype.golden")
+	if *update {
+		if err := os.WriteFile(golden, src, 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	want, err := os.ReadFile(golden)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(src, want) {
+		t.Errorf("Generated code doesn't match %s (run go test -update):\n%s", golden, src)
+	}
+}
+
+// TestOpenAPIGolden tests that the OpenAPI components for testdata/shape.go match the golden file
+func TestOpenAPIGolden(t *testing.T) {
+	st, _, err := loadSumType("testdata", "shape", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	doc, err := openAPI(st)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	golden := filepath.Join("testdata", "shape_openapi.golden")
+	if *update {
+		if err := os.WriteFile(golden, doc, 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	want, err := os.ReadFile(golden)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(doc, want) {
+		t.Errorf("OpenAPI document doesn't match %s (run go test -update):\n%s", golden, doc)
+	}
+}
+
+// TestSumTypeModel tests the kinds and projections derived from the sumtype struct tags
+func TestSumTypeModel(t *testing.T) {
+	st, _, err := loadSumType("testdata", "shape", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if st.Common != "Shape" || st.Caster != "shapeCaster" || st.KindType != "ShapeKind" || !st.KindIsPtr || !st.DeclareKindType {
+		t.Errorf("Unexpected sum type: %+v", st)
+	}
+	if len(st.Kinds) != 2 || st.Kinds[0].Const != "CircleShapeKind" || st.Kinds[1].Projection != "RectangleShape" {
+		t.Errorf("Unexpected kinds: %+v", st.Kinds)
+	}
+
+	names := func(p projection) string {
+		var s []string
+		for _, f := range p.Fields {
+			s = append(s, f.Name)
+		}
+		return strings.Join(s, ",")
+	}
+	want := []string{"Color,Kind,_,_,_", "Color,Kind,Radius,_,_", "Color,Kind,_,Width,Height"}
+	for i, p := range st.Projections() {
+		if got := names(p); got != want[i] {
+			t.Errorf("%s fields: expected %s, got %s", p.Name, want[i], got)
+		}
+	}
+}
+
+// TestGoName tests converting JSON kind values to Go identifiers
+func TestGoName(t *testing.T) {
+	for value, want := range map[string]string{"circle": "Circle", "rounded-rect": "RoundedRect", "two_d": "TwoD"} {
+		if got := goName(value); got != want {
+			t.Errorf("goName(%q): expected %s, got %s", value, want, got)
+		}
+	}
+}
+
+// TestSumTypeErrors tests that malformed Json structs are rejected
+func TestSumTypeErrors(t *testing.T) {
+	tests := map[string]string{
+		"no discriminator": "type shape struct {\n\tshapeCaster\n\tColor *string `sumtype:\"kind=a\"`\n}",
+		"no kinds":         "type shape struct {\n\tshapeCaster\n\tKind *ShapeKind `sumtype:\"discriminator\"`\n}",
+		"no caster":        "type shape struct {\n\tKind *ShapeKind `sumtype:\"discriminator,kinds=a\"`\n}",
+		"bad option":       "type shape struct {\n\tshapeCaster\n\tKind *ShapeKind `sumtype:\"discriminator,kinds=a\"`\n\tX int `sumtype:\"bogus\"`\n}",
+	}
+	for name, decl := range tests {
+		t.Run(name, func(t *testing.T) {
+			dir := t.TempDir()
+			if err := os.WriteFile(filepath.Join(dir, "shape.go"), []byte("package p\n\n"+decl+"\n"), 0o644); err != nil {
+				t.Fatal(err)
+			}
+			if _, _, err := loadSumType(dir, "shape", ""); err == nil {
+				t.Error("Expected an error")
+			} else {
+				t.Log(err)
+			}
+		})
+	}
+}
+
+// TestSchemaGolden tests that generating code from OpenAPI and JSON Schema files matches the golden files
+func TestSchemaGolden(t *testing.T) {
+	tests := []struct{ schema, typeName, golden string }{
+		{"shape_openapi.golden", "shape", "shape_schema.golden"}, // Round-trips sumtypegen -openapi's output
+		{"pet.schema.json", "pet", "pet_sumtype.golden"},
+	}
+	for _, test := range tests {
+		t.Run(test.typeName, func(t *testing.T) {
+			st, err := loadSchemaSumType("testdata", filepath.Join("testdata", test.schema), test.typeName, "")
+			if err != nil {
+				t.Fatal(err)
+			}
+			src, err := generate(st)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			golden := filepath.Join("testdata", test.golden)
+			if *update {
+				if err := os.WriteFile(golden, src, 0o644); err != nil {
+					t.Fatal(err)
+				}
+			}
+			want, err := os.ReadFile(golden)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !bytes.Equal(src, want) {
+				t.Errorf("Generated code doesn't match %s (run go test -update):\n%s", golden, src)
+			}
+		})
+	}
+}
+
+// TestSchemaModel tests the kinds and fields derived from a JSON Schema oneOf
+func TestSchemaModel(t *testing.T) {
+	st, err := loadSchemaSumType("testdata", filepath.Join("testdata", "pet.schema.json"), "pet", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if st.Package != "shapes" || st.KindField != "Species" || st.KindType != "PetKind" || !st.DeclareJson {
+		t.Errorf("Unexpected sum type: %+v", st)
+	}
+	var kinds []string
+	for _, k := range st.Kinds {
+		kinds = append(kinds, k.Value)
+	}
+	if got := strings.Join(kinds, ","); got != "dog,cat,goldfish" {
+		t.Errorf("Unexpected kinds: %s", got)
+	}
+	want := map[string]string{"Born": "*time.Time", "Weight": "*float64 dog|cat", "Tricks": "[]string dog",
+		"Toys": "map[string]int64 cat", "Owner": "jsontext.Value cat"}
+	for _, f := range st.Fields {
+		got := strings.TrimSpace(f.Type + " " + strings.Join(f.Kinds, "|"))
+		if w, ok := want[f.Name]; ok && got != w {
+			t.Errorf("Field %s: expected %s, got %s", f.Name, w, got)
+		}
+	}
+}
+
+// TestSchemaErrors tests that schemas that aren't discriminated oneOfs are rejected
+func TestSchemaErrors(t *testing.T) {
+	tests := map[string]string{
+		"no oneOf":          `{"type": "object"}`,
+		"no discriminator":  `{"oneOf": [{"properties": {"a": {"type": "string"}}}]}`,
+		"unresolved ref":    `{"oneOf": [{"$ref": "#/$defs/Missing"}]}`,
+		"duplicate kind":    `{"oneOf": [{"properties": {"k": {"const": "a"}}}, {"properties": {"k": {"const": "a"}}}]}`,
+		"conflicting types": `{"oneOf": [{"properties": {"k": {"const": "a"}, "x": {"type": "string"}}}, {"properties": {"k": {"const": "b"}, "x": {"type": "integer"}}}]}`,
+		"bad property name": `{"oneOf": [{"properties": {"k": {"const": "a"}, "42": {"type": "string"}}}]}`,
+	}
+	for name, schema := range tests {
+		t.Run(name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "schema.json")
+			if err := os.WriteFile(path, []byte(schema), 0o644); err != nil {
+				t.Fatal(err)
+			}
+			if _, err := loadSchemaSumType("testdata", path, "shape", ""); err == nil {
+				t.Error("Expected an error")
+			} else {
+				t.Log(err)
+			}
+		})
+	}
+}
+
+// TestSchemaEscaping tests that kind values needing escapes produce valid Go and that
+// descriptions become Go doc comments
+func TestSchemaEscaping(t *testing.T) {
+	schema := `{"oneOf": [{"properties": {"k": {"const": "a\"b"}, "x": {"type": "string", "description": "The x value."}}},
+		{"properties": {"k": {"const": "c\\d"}, "y": {"type": "string", "description": "Counts the ys."}}}]}`
+	path := filepath.Join(t.TempDir(), "schema.json")
+	if err := os.WriteFile(path, []byte(schema), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	st, err := loadSchemaSumType("testdata", path, "shape", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	src, err := generate(st)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{`= "a\"b"`, `= "c\\d"`, "// X is the x value.", "// Counts the ys."} {
+		if !bytes.Contains(src, []byte(want)) {
+			t.Errorf("Expected generated code to contain %s, got:\n%s", want, src)
+		}
+	}
+}
@@ -0,0 +1,331 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/token"
+	"path/filepath"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// sumType describes everything needed to generate a sum type's projections and methods.
+type sumType struct {
+	Package         string   // Package is the Go package the generated file belongs to
+	Json            string   // Json is the private JSONable struct's name (ex: shape)
+	Common          string   // Common is the public projection exposing shared fields (ex: Shape)
+	Caster          string   // Caster is the unexported xxxCaster type's name (ex: shapeCaster)
+	KindType        string   // KindType is the discriminator's type (ex: ShapeKind)
+	KindField       string   // KindField is the discriminator field's name (ex: Kind)
+	KindIsPtr       bool     // KindIsPtr is true if the discriminator field is *KindType
+	DeclareKindType bool     // DeclareKindType is true if KindType must be generated
+	DeclareJson     bool     // DeclareJson is true if the Json struct must be generated
+	Imports         []string // Imports are the packages the field types need besides the standard ones
+	Fields          []field  // Fields are Json's fields after the xxxCaster field
+	Kinds           []kind   // Kinds are the discriminator values in order of declaration
+
+	typeDecls map[string]ast.Expr // typeDecls maps the package's type names to their definitions
+}
+
+// field describes one of the Json struct's data fields.
+type field struct {
+	Name  string   // Name is the Go field name
+	Type  string   // Type is the Go source for the field's type
+	Tag   string   // Tag is the Go source for the field's struct tag (including backquotes) or ""
+	Doc   []string // Doc is the field's doc comment lines (without "//")
+	Kinds []string // Kinds are the kinds exposing this field; nil means all kinds
+
+	// Constraints are the field's Caster.Validate options (ex: required, min=0)
+	Constraints []string
+
+	jsonName string   // jsonName is the field's JSON member name or "" if it isn't a named member
+	expr     ast.Expr // expr is the field's type expression
+}
+
+// kind describes one discriminator value and its projection.
+type kind struct {
+	Value      string // Value is the discriminator's JSON value (ex: circle)
+	Name       string // Name is the Go identifier for the kind (ex: Circle)
+	Const      string // Const is the kind constant's name (ex: CircleShapeKind)
+	Projection string // Projection is the projection struct's name (ex: CircleShape)
+}
+
+// projection describes a projection struct to generate.
+type projection struct {
+	Name   string            // Name is the projection struct's name
+	Doc    string            // Doc is the projection's doc comment
+	Fields []projectionField // Fields are the projection's data fields
+}
+
+// projectionField describes a field of a projection struct; hidden fields are named "_".
+type projectionField struct {
+	Name string
+	Type string
+	Tag  string // Tag is the Go source for the field's sumtype constraint tag or ""
+	Doc  []string
+}
+
+// Projections returns the common projection followed by one projection per kind.
+func (st *sumType) Projections() []projection {
+	projections := []projection{st.projection(st.Common,
+		fmt.Sprintf("%s is public and exposes fields common to all %s kinds", st.Common, st.Json), "")}
+	for _, k := range st.Kinds {
+		projections = append(projections, st.projection(k.Projection,
+			fmt.Sprintf("%s is public and exposes fields related to a %s kind.", k.Projection, k.Value), k.Value))
+	}
+	return projections
+}
+
+// projection returns a projection exposing the common fields plus the fields of kind (if not "").
+func (st *sumType) projection(name, doc, kind string) projection {
+	p := projection{Name: name, Doc: doc}
+	for _, f := range st.Fields {
+		pf := projectionField{Name: f.Name, Type: f.Type, Doc: f.Doc}
+		if f.Kinds != nil && (kind == "" || !slices.Contains(f.Kinds, kind)) {
+			pf.Name, pf.Doc = "_", hiddenDoc(f.Name, f.Doc)
+		} else if f.Constraints != nil {
+			pf.Tag = fmt.Sprintf("`sumtype:%q`", strings.Join(f.Constraints, ","))
+		}
+		p.Fields = append(p.Fields, pf)
+	}
+	return p
+}
+
+// hiddenDoc lowercases the field name when it starts the doc comment, like hand-written projections do.
+func hiddenDoc(name string, doc []string) []string {
+	if len(doc) == 0 || !strings.HasPrefix(doc[0], " "+name+" ") {
+		return doc
+	}
+	return append([]string{" " + lowerFirst(name) + doc[0][len(name)+1:]}, doc[1:]...)
+}
+
+// loadSumType parses the Go files in dir and returns the sum type described by the typeName struct
+// along with the path of the file declaring it.
+func loadSumType(dir, typeName, common string) (*sumType, string, error) {
+	paths, err := filepath.Glob(filepath.Join(dir, "*.go"))
+	if err != nil {
+		return nil, "", err
+	}
+	fset, files := token.NewFileSet(), map[string]*ast.File{}
+	for _, path := range paths {
+		if strings.HasSuffix(path, "_sumtype.go") || strings.HasSuffix(path, "_sumtype_test.go") {
+			continue // Never read our own output
+		}
+		f, err := parser.ParseFile(fset, path, nil, parser.ParseComments)
+		if err != nil {
+			return nil, "", err
+		}
+		files[path] = f
+	}
+
+	for path, f := range files {
+		spec := findType(f, typeName)
+		if spec == nil {
+			continue
+		}
+		st, err := newSumType(fset, f.Name.Name, spec, common)
+		if err != nil {
+			return nil, "", err
+		}
+		st.typeDecls = map[string]ast.Expr{}
+		for _, other := range files {
+			if other.Name.Name == st.Package {
+				addTypeDecls(other, st.typeDecls)
+			}
+		}
+		if _, declared := st.typeDecls[st.KindType]; !declared {
+			st.DeclareKindType, st.typeDecls[st.KindType] = true, ast.NewIdent("string")
+		}
+		return st, path, nil
+	}
+	return nil, "", fmt.Errorf("type %s not found in %s", typeName, dir)
+}
+
+// findType returns the type spec named name declared in f or nil if there isn't one.
+func findType(f *ast.File, name string) *ast.TypeSpec {
+	for _, decl := range f.Decls {
+		if gd, ok := decl.(*ast.GenDecl); ok && gd.Tok == token.TYPE {
+			for _, s := range gd.Specs {
+				if ts := s.(*ast.TypeSpec); ts.Name.Name == name {
+					return ts
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// addTypeDecls adds the types declared in f to decls.
+func addTypeDecls(f *ast.File, decls map[string]ast.Expr) {
+	for _, decl := range f.Decls {
+		if gd, ok := decl.(*ast.GenDecl); ok && gd.Tok == token.TYPE {
+			for _, s := range gd.Specs {
+				decls[s.(*ast.TypeSpec).Name.Name] = s.(*ast.TypeSpec).Type
+			}
+		}
+	}
+}
+
+// newSumType builds a sumType from the annotated Json struct's type spec.
+func newSumType(fset *token.FileSet, pkg string, spec *ast.TypeSpec, common string) (*sumType, error) {
+	name := spec.Name.Name
+	s, ok := spec.Type.(*ast.StructType)
+	if !ok {
+		return nil, fmt.Errorf("type %s must be a struct", name)
+	}
+	if common == "" {
+		common = upperFirst(name)
+	}
+	st := &sumType{Package: pkg, Json: name, Common: common, Caster: lowerFirst(name) + "Caster"}
+	if st.Common == st.Json {
+		return nil, fmt.Errorf("type %s must be unexported (or use -common to name the common projection)", name)
+	}
+
+	fields := s.Fields.List
+	if len(fields) == 0 || len(fields[0].Names) != 0 || exprString(fset, fields[0].Type) != st.Caster {
+		return nil, fmt.Errorf("first field of struct %s must be the embedded %s", name, st.Caster)
+	}
+
+	for _, f := range fields[1:] {
+		if len(f.Names) == 0 {
+			return nil, fmt.Errorf("struct %s: embedded field %s is not supported", name, exprString(fset, f.Type))
+		}
+		tags, rawTag := reflect.StructTag(""), ""
+		if f.Tag != nil {
+			unquoted, _ := strconv.Unquote(f.Tag.Value)
+			tags, rawTag = reflect.StructTag(unquoted), f.Tag.Value
+		}
+		tag := tags.Get("sumtype")
+		for _, n := range f.Names {
+			fld := field{Name: n.Name, Type: exprString(fset, f.Type), Tag: rawTag, Doc: docLines(f.Doc),
+				jsonName: jsonName(n.Name, tags), expr: f.Type}
+			if err := st.applyTag(&fld, f.Type, tag); err != nil {
+				return nil, fmt.Errorf("struct %s field %s: %w", name, n.Name, err)
+			}
+			st.Fields = append(st.Fields, fld)
+		}
+	}
+	if st.KindField == "" {
+		return nil, fmt.Errorf(`struct %s has no field tagged sumtype:"discriminator"`, name)
+	}
+	if len(st.Kinds) == 0 {
+		return nil, fmt.Errorf("struct %s declares no kinds", name)
+	}
+	return st, nil
+}
+
+// applyTag applies a field's sumtype struct tag: "discriminator[,kinds=a|b]" or "kind=a|b" plus
+// any of the constraints "required", "min=N" and "max=N" which are copied to the projections.
+func (st *sumType) applyTag(f *field, typ ast.Expr, tag string) error {
+	for _, option := range strings.Split(tag, ",") {
+		key, value, _ := strings.Cut(strings.TrimSpace(option), "=")
+		switch key {
+		case "":
+		case "discriminator":
+			if st.KindField != "" {
+				return fmt.Errorf("%s is already the discriminator", st.KindField)
+			}
+			st.KindField = f.Name
+			if star, ok := typ.(*ast.StarExpr); ok {
+				st.KindIsPtr, typ = true, star.X
+			}
+			ident, ok := typ.(*ast.Ident)
+			if !ok {
+				return fmt.Errorf("discriminator type must be a named type declared in this package")
+			}
+			st.KindType = ident.Name
+
+		case "kinds":
+			if f.Name != st.KindField {
+				return fmt.Errorf("kinds= is only valid on the discriminator")
+			}
+			for _, v := range strings.Split(value, "|") {
+				st.addKind(v)
+			}
+
+		case "kind":
+			f.Kinds = strings.Split(value, "|")
+			for _, v := range f.Kinds {
+				st.addKind(v)
+			}
+
+		case "required", "min", "max":
+			f.Constraints = append(f.Constraints, strings.TrimSpace(option))
+
+		default:
+			return fmt.Errorf("unrecognized sumtype tag option %q", key)
+		}
+	}
+	return nil
+}
+
+// addKind adds the kind with the specified JSON value if it hasn't been added already.
+func (st *sumType) addKind(value string) {
+	for _, k := range st.Kinds {
+		if k.Value == value {
+			return
+		}
+	}
+	name := goName(value)
+	st.Kinds = append(st.Kinds, kind{Value: value, Name: name,
+		Const: name + st.Common + "Kind", Projection: name + st.Common})
+}
+
+// jsonName returns the JSON member name of an exported field with the specified struct tags or
+// "" if the field isn't marshaled as a named member.
+func jsonName(name string, tags reflect.StructTag) string {
+	tag, hasTag := tags.Lookup("json")
+	jsonName, options, _ := strings.Cut(tag, ",")
+	if !ast.IsExported(name) || tag == "-" || slices.Contains(strings.Split(options, ","), "embed") {
+		return ""
+	}
+	if !hasTag || jsonName == "" {
+		return name
+	}
+	return strings.Trim(jsonName, "'")
+}
+
+// exprString returns the Go source for the type expression.
+func exprString(fset *token.FileSet, expr ast.Expr) string {
+	var b bytes.Buffer
+	_ = format.Node(&b, fset, expr)
+	return b.String()
+}
+
+// docLines returns the lines of a doc comment without their leading "//".
+func docLines(cg *ast.CommentGroup) []string {
+	if cg == nil {
+		return nil
+	}
+	var lines []string
+	for _, c := range cg.List {
+		lines = append(lines, strings.TrimPrefix(c.Text, "//"))
+	}
+	return lines
+}
+
+// goName converts a JSON kind value like "rounded-rect" to a Go identifier like "RoundedRect".
+func goName(value string) string {
+	var b strings.Builder
+	upper := true
+	for _, r := range value {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) {
+			upper = true
+			continue
+		}
+		if upper {
+			r, upper = unicode.ToUpper(r), false
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+func upperFirst(s string) string { return strings.ToUpper(s[:1]) + s[1:] }
+func lowerFirst(s string) string { return strings.ToLower(s[:1]) + s[1:] }
@@ -0,0 +1,105 @@
+package main
+
+import (
+	"encoding/json/jsontext"
+	"encoding/json/v2"
+	"fmt"
+	"go/ast"
+	"reflect"
+	"slices"
+	"strconv"
+
+	"github.com/JeffreyRichter/sumtype"
+)
+
+// openAPIRef is the prefix of references to OpenAPI component schemas.
+const openAPIRef = "#/components/schemas/"
+
+// openAPI returns an OpenAPI 3.1 document fragment with st's components.schemas entries, like
+// sumtype.OpenAPISchemas returns for the registered sum type at runtime.
+func openAPI(st *sumType) ([]byte, error) {
+	var kindName string
+	base := &sumtype.Schema{Type: "object", Properties: map[string]*sumtype.Schema{}}
+	for _, f := range st.Fields {
+		if f.Name == st.KindField {
+			kindName = f.jsonName
+		}
+		if f.jsonName != "" && f.Kinds == nil {
+			base.Properties[f.jsonName] = st.schema(f.expr, map[string]bool{})
+		}
+	}
+	if kindName == "" {
+		return nil, fmt.Errorf("discriminator %s.%s must be a JSON member", st.Json, st.KindField)
+	}
+	base.Required = []string{kindName}
+	base.Discriminator = &sumtype.Discriminator{PropertyName: kindName, Mapping: map[string]string{}}
+
+	schemas := map[string]*sumtype.Schema{st.Common: base}
+	for _, k := range st.Kinds {
+		base.Discriminator.Mapping[k.Value] = openAPIRef + k.Projection
+		specific := &sumtype.Schema{Type: "object", Properties: map[string]*sumtype.Schema{}}
+		for _, f := range st.Fields {
+			if f.jsonName != "" && slices.Contains(f.Kinds, k.Value) {
+				specific.Properties[f.jsonName] = st.schema(f.expr, map[string]bool{})
+			}
+		}
+		schemas[k.Projection] = &sumtype.Schema{AllOf: []*sumtype.Schema{{Ref: openAPIRef + st.Common}, specific}}
+	}
+
+	doc := map[string]any{"components": map[string]any{"schemas": schemas}}
+	return json.Marshal(doc, json.Deterministic(true), jsontext.WithIndent("  "))
+}
+
+// schema returns the schema for values of the Go type expr as marshaled by encoding/json/v2.
+// visiting contains the package's type names being described to stop recursion on recursive types.
+func (st *sumType) schema(expr ast.Expr, visiting map[string]bool) *sumtype.Schema {
+	switch e := expr.(type) {
+	case *ast.StarExpr:
+		return st.schema(e.X, visiting)
+	case *ast.ParenExpr:
+		return st.schema(e.X, visiting)
+	case *ast.Ident:
+		switch e.Name {
+		case "string":
+			return &sumtype.Schema{Type: "string"}
+		case "bool":
+			return &sumtype.Schema{Type: "boolean"}
+		case "int", "int8", "int16", "int32", "int64", "uint", "uint8", "uint16", "uint32", "uint64", "uintptr", "byte", "rune":
+			return &sumtype.Schema{Type: "integer"}
+		case "float32", "float64":
+			return &sumtype.Schema{Type: "number"}
+		}
+		if decl, ok := st.typeDecls[e.Name]; ok && !visiting[e.Name] {
+			visiting[e.Name] = true
+			defer delete(visiting, e.Name)
+			return st.schema(decl, visiting)
+		}
+	case *ast.SelectorExpr:
+		if pkg, ok := e.X.(*ast.Ident); ok && pkg.Name == "time" && e.Sel.Name == "Time" {
+			return &sumtype.Schema{Type: "string", Format: "date-time"}
+		}
+	case *ast.ArrayType:
+		if elem, ok := e.Elt.(*ast.Ident); ok && (elem.Name == "byte" || elem.Name == "uint8") {
+			return &sumtype.Schema{Type: "string", ContentEncoding: "base64"}
+		}
+		return &sumtype.Schema{Type: "array", Items: st.schema(e.Elt, visiting)}
+	case *ast.MapType:
+		return &sumtype.Schema{Type: "object", AdditionalProperties: st.schema(e.Value, visiting)}
+	case *ast.StructType:
+		schema := &sumtype.Schema{Type: "object", Properties: map[string]*sumtype.Schema{}}
+		for _, f := range e.Fields.List {
+			tags := reflect.StructTag("")
+			if f.Tag != nil {
+				unquoted, _ := strconv.Unquote(f.Tag.Value)
+				tags = reflect.StructTag(unquoted)
+			}
+			for _, n := range f.Names {
+				if name := jsonName(n.Name, tags); name != "" {
+					schema.Properties[name] = st.schema(f.Type, visiting)
+				}
+			}
+		}
+		return schema
+	}
+	return &sumtype.Schema{} // Any JSON value
+}
@@ -0,0 +1,24 @@
+package shapes
+
+//go:generate go run github.com/JeffreyRichter/sumtype/cmd/sumtypegen -type=shape
+
+// shape is package-private and used for (un)marshaling (all data fields are public).
+type shape struct {
+	// shapeCaster MUST be 1st field, unexported & embedded for method "inheritance"
+	shapeCaster
+
+	// Color is the color of the shape (shared by all shapes)
+	Color *string `json:"color,omitempty"`
+
+	// Kind is the discriminator indicating which type of Shape (shared by all shapes)
+	Kind *ShapeKind `json:"kind,omitempty" sumtype:"discriminator"`
+
+	// Radius is the radius of a circle shape
+	Radius *int `json:"radius,omitempty" sumtype:"kind=circle,required,min=0"`
+
+	// Width is the width of a rectangle shape
+	Width *int `json:"width,omitempty" sumtype:"kind=rectangle,required,min=0"`
+
+	// Height is the height of a rectangle shape
+	Height *int `json:"height,omitempty" sumtype:"kind=rectangle,required,min=0"`
+}
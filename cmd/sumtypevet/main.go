@@ -0,0 +1,12 @@
+// Sumtypevet checks sum types built on sumtype.Caster. Run it via go vet:
+//
+//	go install github.com/JeffreyRichter/sumtype/cmd/sumtypevet
+//	go vet -vettool=$(which sumtypevet) ./...
+package main
+
+import (
+	"github.com/JeffreyRichter/sumtype/analyzer"
+	"golang.org/x/tools/go/analysis/unitchecker"
+)
+
+func main() { unitchecker.Main(analyzer.Analyzer, analyzer.ExhaustiveAnalyzer) }
@@ -0,0 +1,179 @@
+package sumtype
+
+import (
+	"encoding/binary"
+	"hash/maphash"
+	"math"
+	"reflect"
+	"sync"
+)
+
+// relevantFieldSets maps [2]reflect.Type{Json, projection} to the indexes of the Json fields
+// relevant to the projection's kind.
+var relevantFieldSets sync.Map
+
+// relevantFields returns the indexes of the exported Json fields that projection exports (all
+// exported Json fields if projection is nil). The result is computed once and cached.
+func relevantFields(json, projection reflect.Type) []int {
+	key := [2]reflect.Type{json, projection}
+	if fields, ok := relevantFieldSets.Load(key); ok {
+		return fields.([]int)
+	}
+	fields := []int{}
+	for f := range json.NumField() {
+		if json.Field(f).IsExported() && (projection == nil || projection.Field(f).IsExported()) {
+			fields = append(fields, f)
+		}
+	}
+	actual, _ := relevantFieldSets.LoadOrStore(key, fields)
+	return actual.([]int)
+}
+
+// currentFields returns the Json struct's value and the indexes of the fields relevant to its
+// current kind: the fields exported by the kind's registered projection or, if there's no
+// projection, all exported fields.
+func (c *Caster[Json]) currentFields() (reflect.Value, []int) {
+	json := reflect.ValueOf(c.Json()).Elem()
+	var projection reflect.Type
+	if r, err := registrationFor[Json](); err == nil {
+		projection, _ = r.projection(r.kind(json))
+	}
+	return json, relevantFields(json.Type(), projection)
+}
+
+// Equal returns true if c and other have the same kind and their fields exposed by that kind's
+// projection are deeply equal (pointer fields are compared by the values they point to), so two
+// circles differing only in a leftover Width are equal. If the kind is nil, unregistered, or Json
+// isn't registered, all exported fields are compared.
+func (c *Caster[Json]) Equal(other *Caster[Json]) bool {
+	json, fields := c.currentFields()
+	otherJSON := reflect.ValueOf(other.Json()).Elem()
+	if r, err := registrationFor[Json](); err == nil {
+		kind, ok := r.kind(json)
+		otherKind, otherOk := r.kind(otherJSON)
+		if ok != otherOk || kind != otherKind {
+			return false
+		}
+	}
+	for _, f := range fields {
+		if !reflect.DeepEqual(json.Field(f).Interface(), otherJSON.Field(f).Interface()) {
+			return false
+		}
+	}
+	return true
+}
+
+// Hash returns a hash of the fields Equal compares so sum type values can be deduplicated in
+// maps; Equal values have the same hash for the same seed. Hash handles cyclic data by not
+// following a reference back into a value it's already hashing, but Equal values whose cycles
+// have different shapes (like a node pointing to itself and 2 equal nodes pointing to each
+// other) may then hash differently.
+func (c *Caster[Json]) Hash(seed maphash.Seed) uint64 {
+	var h maphash.Hash
+	h.SetSeed(seed)
+	json, fields := c.currentFields()
+	path := map[hashVisit]bool{}
+	for _, f := range fields {
+		hashValue(&h, seed, json.Field(f), path)
+	}
+	return h.Sum64()
+}
+
+// hashVisit identifies a pointer, slice, or map being hashed.
+type hashVisit struct {
+	ptr uintptr      // ptr is the address of the referenced value
+	typ reflect.Type // typ is the reference's type
+	len int          // len is a slice's length (slices sharing an array may differ in length)
+}
+
+// hashValue writes v to h such that values reflect.DeepEqual considers equal write the same
+// bytes. Only the exported fields of nested structs are written; maps are hashed independently
+// of their iteration order using seed. path contains the references being hashed; a reference
+// back to one of them writes a marker instead of recursing forever.
+func hashValue(h *maphash.Hash, seed maphash.Seed, v reflect.Value, path map[hashVisit]bool) {
+	var buf [8]byte
+	writeUint := func(n uint64) { h.Write(binary.LittleEndian.AppendUint64(buf[:0], n)) }
+	writeFloat := func(f float64) {
+		if f == 0 {
+			f = 0 // DeepEqual considers -0 and +0 equal
+		}
+		writeUint(math.Float64bits(f))
+	}
+
+	switch v.Kind() {
+	case reflect.Pointer, reflect.Map, reflect.Slice:
+		if !v.IsNil() {
+			visit := hashVisit{v.Pointer(), v.Type(), 0}
+			if v.Kind() == reflect.Slice {
+				visit.len = v.Len()
+			}
+			if path[visit] {
+				h.WriteByte(2)
+				return
+			}
+			path[visit] = true
+			defer delete(path, visit)
+		}
+	}
+
+	switch v.Kind() {
+	case reflect.Pointer, reflect.Interface:
+		if v.IsNil() {
+			h.WriteByte(0)
+			return
+		}
+		h.WriteByte(1)
+		hashValue(h, seed, v.Elem(), path)
+	case reflect.Bool:
+		if v.Bool() {
+			h.WriteByte(1)
+		} else {
+			h.WriteByte(0)
+		}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		writeUint(uint64(v.Int()))
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		writeUint(v.Uint())
+	case reflect.Float32, reflect.Float64:
+		writeFloat(v.Float())
+	case reflect.Complex64, reflect.Complex128:
+		writeFloat(real(v.Complex()))
+		writeFloat(imag(v.Complex()))
+	case reflect.String:
+		writeUint(uint64(v.Len()))
+		h.WriteString(v.String())
+	case reflect.Slice, reflect.Array:
+		if v.Kind() == reflect.Slice && v.IsNil() {
+			h.WriteByte(0) // DeepEqual distinguishes nil and empty slices
+			return
+		}
+		h.WriteByte(1)
+		writeUint(uint64(v.Len()))
+		for i := range v.Len() {
+			hashValue(h, seed, v.Index(i), path)
+		}
+	case reflect.Map:
+		if v.IsNil() {
+			h.WriteByte(0)
+			return
+		}
+		h.WriteByte(1)
+		var sum uint64 // The sum of the entries' hashes doesn't depend on iteration order
+		for k, e := range v.Seq2() {
+			var entry maphash.Hash
+			entry.SetSeed(seed)
+			hashValue(&entry, seed, k, path)
+			hashValue(&entry, seed, e, path)
+			sum += entry.Sum64()
+		}
+		writeUint(uint64(v.Len()))
+		writeUint(sum)
+	case reflect.Struct:
+		for f := range v.NumField() {
+			if v.Type().Field(f).IsExported() {
+				hashValue(h, seed, v.Field(f), path)
+			}
+		}
+	}
+	// Channels, functions, and unsafe pointers don't contribute to the hash
+}
@@ -0,0 +1,97 @@
+package sumtype_test
+
+import (
+	"hash/maphash"
+	"testing"
+
+	"github.com/JeffreyRichter/sumtype"
+)
+
+// TestEqual tests that only the fields exposed by the current kind's projection are compared
+func TestEqual(t *testing.T) {
+	seed := maphash.MakeSeed()
+	circle := func(color string, radius int) *Shape {
+		c := (&Shape{}).SetCircle()
+		c.Color, c.Radius = ptr(color), ptr(radius)
+		return c.Shape()
+	}
+	a, b := circle("red", 1), circle("red", 1)
+	b.json().Width = ptr(5) // A leftover field irrelevant to circles
+
+	tests := []struct {
+		name  string
+		a, b  *Shape
+		equal bool
+	}{
+		{"leftover field", a, b, true},
+		{"different radius", a, circle("red", 2), false},
+		{"different color", a, circle("blue", 1), false},
+		{"different kind", a, func() *Shape { s := circle("red", 1); s.SetRectangle(); return s }(), false},
+		{"nil kinds", &Shape{Color: ptr("red")}, &Shape{Color: ptr("red")}, true},
+		{"nil and non-nil kind", &Shape{}, a, false},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if got := test.a.caster().Equal(test.b.caster()); got != test.equal {
+				t.Errorf("Expected Equal to return %t", test.equal)
+			}
+			if got := test.b.caster().Equal(test.a.caster()); got != test.equal {
+				t.Errorf("Expected Equal to be symmetric")
+			}
+			if test.equal && test.a.caster().Hash(seed) != test.b.caster().Hash(seed) {
+				t.Error("Expected equal values to have equal hashes")
+			}
+		})
+	}
+
+	// Deduplicate shapes in a map keyed by their hash
+	byHash := map[uint64]*Shape{}
+	for _, s := range []*Shape{a, b, circle("red", 2)} {
+		byHash[s.caster().Hash(seed)] = s
+	}
+	if len(byHash) != 2 {
+		t.Errorf("Expected 2 distinct shapes, got %d", len(byHash))
+	}
+}
+
+type (
+	// labels is an unregistered sum type whose exported fields are all compared
+	labels struct {
+		labelsCaster
+		Labels map[string]float64
+	}
+
+	labelsCaster sumtype.Caster[labels]
+)
+
+// TestHashMaps tests that hashing a map doesn't depend on its iteration order
+func TestHashMaps(t *testing.T) {
+	seed := maphash.MakeSeed()
+	a, b := &labels{Labels: map[string]float64{}}, &labels{Labels: map[string]float64{}}
+	for i := range 100 {
+		a.Labels[string(rune('a'+i))] = float64(i)
+	}
+	for i := 99; i >= 0; i-- {
+		b.Labels[string(rune('a'+i))] = float64(i)
+	}
+	b.Labels["a"] = -a.Labels["a"] // -0 equals +0
+	ca, cb := (*sumtype.Caster[labels])(&a.labelsCaster), (*sumtype.Caster[labels])(&b.labelsCaster)
+	if !ca.Equal(cb) || ca.Hash(seed) != cb.Hash(seed) {
+		t.Error("Expected equal maps to have equal hashes")
+	}
+}
+
+// TestHashCycles tests that hashing cyclic data terminates and equal cycles have equal hashes
+func TestHashCycles(t *testing.T) {
+	seed := maphash.MakeSeed()
+	cycle := func() *sumtype.Caster[tree] {
+		n := &tree{Kind: "node", Any: []any{nil}}
+		n.Children = []*tree{n}
+		n.Any.([]any)[0] = n.Any
+		return (*sumtype.Caster[tree])(&n.treeCaster)
+	}
+	a, b := cycle(), cycle()
+	if !a.Equal(b) || a.Hash(seed) != b.Hash(seed) {
+		t.Error("Expected equal cycles to have equal hashes")
+	}
+}
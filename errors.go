@@ -0,0 +1,105 @@
+package sumtype
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+var (
+	// ErrNilKind indicates that a sum type's discriminator is a nil pointer.
+	ErrNilKind = errors.New("sumtype: kind is nil")
+
+	// ErrNotRegistered indicates that a sum type's Json struct was never passed to Register.
+	ErrNotRegistered = errors.New("sumtype: sum type is not registered")
+)
+
+// KindMismatchError indicates that a sum type's discriminator doesn't have the expected kind.
+type KindMismatchError struct {
+	Have any // Have is the current kind
+	Want any // Want is the expected kind
+}
+
+func (e *KindMismatchError) Error() string {
+	return fmt.Sprintf("sumtype: can't cast from kind %v to kind %v", e.Have, e.Want)
+}
+
+// ProjectionError indicates that a type isn't the projection registered for a kind.
+type ProjectionError struct {
+	Kind       any          // Kind is the requested kind
+	Projection reflect.Type // Projection is the projection registered for Kind
+	Type       reflect.Type // Type is the requested projection type
+}
+
+func (e *ProjectionError) Error() string {
+	return fmt.Sprintf("sumtype: kind %v's projection is %s, not %s", e.Kind, e.Projection, e.Type)
+}
+
+// TransitionError indicates that a sum type's kind isn't allowed to change to another kind (see
+// SetTransitions).
+type TransitionError struct {
+	From any // From is the current kind
+	To   any // To is the requested kind
+}
+
+func (e *TransitionError) Error() string {
+	return fmt.Sprintf("sumtype: can't change kind from %v to %v", e.From, e.To)
+}
+
+// UnknownKindError indicates that a sum type's discriminator has a kind that isn't registered.
+type UnknownKindError struct {
+	Kind any // Kind is the unregistered kind
+}
+
+func (e *UnknownKindError) Error() string { return fmt.Sprintf("sumtype: unknown kind %v", e.Kind) }
+
+// IrrelevantFieldsError indicates that JSON has members that don't belong to its kind.
+type IrrelevantFieldsError struct {
+	Kind  any      // Kind is the decoded kind
+	Names []string // Names are the JSON names of the members irrelevant to Kind
+}
+
+func (e *IrrelevantFieldsError) Error() string {
+	return fmt.Sprintf("sumtype: kind %v doesn't have members %s", e.Kind, strings.Join(e.Names, ", "))
+}
+
+// ValidationError indicates that a JSON member violates a constraint declared by a sumtype struct
+// tag on the current kind's projection (see Caster.Validate).
+type ValidationError struct {
+	Path   string // Path is the JSON Pointer (RFC 6901) to the member (ex: /radius)
+	Reason string // Reason describes the violated constraint (ex: is required)
+}
+
+func (e *ValidationError) Error() string { return fmt.Sprintf("sumtype: %s %s", e.Path, e.Reason) }
+
+// TryCast casts From a caster To another sum type projection type if the caster's current kind
+// is want. If the kind is nil, TryCast returns ErrNilKind; if it's not want, it returns a
+// *KindMismatchError. Kind must be the discriminator's type, want must be registered (or TryCast
+// returns an *UnknownKindError), and To must be want's projection (or TryCast returns a
+// *ProjectionError). Json must be registered (see Register) so TryCast can find its
+// discriminator; otherwise TryCast returns ErrNotRegistered.
+func TryCast[To any, Json any, Kind comparable](caster *Caster[Json], want Kind) (*To, error) {
+	r, err := registrationFor[Json]()
+	if err != nil {
+		return nil, err
+	}
+	if err := checkKindType[Json](r, want); err != nil {
+		return nil, err
+	}
+	projection, ok := r.projections[want]
+	if !ok {
+		return nil, &UnknownKindError{Kind: want}
+	}
+	if to := reflect.TypeFor[To](); to != projection {
+		return nil, &ProjectionError{Kind: want, Projection: projection, Type: to}
+	}
+	have, ok := r.kind(reflect.ValueOf(caster.Json()).Elem())
+	if !ok {
+		return nil, ErrNilKind
+	}
+	if have != any(want) {
+		return nil, &KindMismatchError{Have: have, Want: want}
+	}
+	return Cast[To](caster), nil
+}
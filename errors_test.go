@@ -0,0 +1,69 @@
+package sumtype_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/JeffreyRichter/sumtype"
+)
+
+// TestTryCast tests kind-checked casts that return errors instead of panicking
+func TestTryCast(t *testing.T) {
+	s := (&CircleShape{Kind: ptr(CircleShapeKind), Radius: ptr(5)}).Shape()
+
+	c, err := s.TryCircle()
+	if err != nil || c != s.Circle() || *c.Radius != 5 {
+		t.Fatalf("Expected the circle, got %v, %v", c, err)
+	}
+
+	_, err = s.TryRectangle()
+	var mismatch *sumtype.KindMismatchError
+	if !errors.As(err, &mismatch) || mismatch.Have != CircleShapeKind || mismatch.Want != RectangleShapeKind {
+		t.Errorf("Expected a *KindMismatchError, got %v", err)
+	}
+
+	var none Shape
+	if _, err = none.TryCircle(); !errors.Is(err, sumtype.ErrNilKind) {
+		t.Errorf("Expected ErrNilKind, got %v", err)
+	}
+
+	var projection *sumtype.ProjectionError
+	if _, err = sumtype.TryCast[RectangleShape](s.caster(), CircleShapeKind); !errors.As(err, &projection) {
+		t.Errorf("Expected a *ProjectionError, got %v", err)
+	}
+
+	if _, err = sumtype.TryCast[CircleShape](s.caster(), "circle"); err == nil || errors.As(err, &mismatch) {
+		t.Errorf("Expected an error for a kind that isn't a ShapeKind, got %v", err)
+	}
+
+	var unknown *sumtype.UnknownKindError
+	if _, err = sumtype.TryCast[CircleShape](s.caster(), ShapeKind("triangle")); !errors.As(err, &unknown) {
+		t.Errorf("Expected an *UnknownKindError, got %v", err)
+	}
+
+	type unregistered struct{ sumtype.Caster[unregistered] }
+	var u unregistered
+	if _, err = sumtype.TryCast[unregistered](&u.Caster, "kind"); !errors.Is(err, sumtype.ErrNotRegistered) {
+		t.Errorf("Expected ErrNotRegistered, got %v", err)
+	}
+}
+
+// TestCastPanics tests that the panicking casts panic with the typed errors
+func TestCastPanics(t *testing.T) {
+	defer func() {
+		err, _ := recover().(error)
+		if !errors.Is(err, sumtype.ErrNilKind) {
+			t.Errorf("Expected a panic with ErrNilKind, got %v", err)
+		}
+	}()
+	var none Shape
+	none.Circle()
+}
+
+// BenchmarkCircle measures the kind-checked cast made by the generated accessors
+func BenchmarkCircle(b *testing.B) {
+	s := (&CircleShape{Kind: ptr(CircleShapeKind)}).Shape()
+	for b.Loop() {
+		_ = s.Circle()
+	}
+}
@@ -77,6 +77,7 @@ func Example() {
 		default:
 			// This can happen if the Web Service returns a new kind (perhaps
 			// in a new version) that this client code never knew about.
+			// See sumtype.SetUnknownKindPolicy to reject or project such kinds instead.
 			fmt.Printf("Unrecognized shape kind: %s\n", *s.Kind)
 		}
 	}
@@ -132,6 +133,12 @@ func Example() {
 // At app initialization, panic if any of shape's projection structs don't match
 var _ = sumtype.Caster[shape]{}.ValidateStructFields(true, Shape{}, CircleShape{}, RectangleShape{})
 
+// At app initialization, register shape's discriminator field and the projection for each kind
+var _ = sumtype.Register[shape](true, "Kind", map[ShapeKind]any{
+	CircleShapeKind:    CircleShape{},
+	RectangleShapeKind: RectangleShape{},
+})
+
 const (
 	// CircleShapeKind is the kind for circle shapes
 	CircleShapeKind ShapeKind = "circle"
@@ -198,7 +205,7 @@ type (
 		Kind *ShapeKind
 
 		// Radius is the radius of a circle shape
-		Radius *int
+		Radius *int `sumtype:"required,min=0"`
 
 		// width is the width of a rectangle shape
 		_ *int
@@ -222,15 +229,15 @@ type (
 		_ *int
 
 		// Width is the width of a rectangle shape
-		Width *int
+		Width *int `sumtype:"required,min=0"`
 
 		// Height is the height of a rectangle shape
-		Height *int
+		Height *int `sumtype:"required,min=0"`
 	}
 
 	// shapeCaster provides methods to cast between *shape and its variants. The 1st field of shape
 	// and all its variants is an unexported shapeCaster whose underlying type is sumtype.Caster[shape].
-	// NOTE: This also hides sumtypes.Caster's MarshalJSON/UnmarshalJSON/String methods so they
+	// NOTE: This also hides sumtypes.Caster's MarshalJSON[To]/UnmarshalJSON[From]/String methods so they
 	// cannot be called directly on shape variants.
 	shapeCaster sumtype.Caster[shape]
 )
@@ -240,7 +247,7 @@ var _ [0]struct{} = [unsafe.Sizeof(shape{}) - unsafe.Sizeof(Shape{})]struct{}{}
 var _ [0]struct{} = [unsafe.Sizeof(shape{}) - unsafe.Sizeof(CircleShape{})]struct{}{}
 var _ [0]struct{} = [unsafe.Sizeof(shape{}) - unsafe.Sizeof(RectangleShape{})]struct{}{}
 
-// RULES: String & MarshalJSON require by-val receiver, UnmarshalJSON requires by-ref receiver
+// RULES: String & MarshalJSON[To] require by-val receiver, UnmarshalJSON[From] requires by-ref receiver
 
 // String returns a readable JSON representation of the shape
 func (s Shape) String() string { return (&s).caster().String() }
@@ -251,6 +258,14 @@ func (s Shape) MarshalJSON() ([]byte, error) { return (&s).caster().MarshalJSON(
 // UnmarshalJSON unmarshals JSON data to the shape
 func (s *Shape) UnmarshalJSON(data []byte) error { return s.caster().UnmarshalJSON(data) }
 
+// MarshalJSONTo marshals the shape to enc
+func (s Shape) MarshalJSONTo(enc *jsontext.Encoder) error { return (&s).caster().MarshalJSONTo(enc) }
+
+// UnmarshalJSONFrom unmarshals the shape from dec
+func (s *Shape) UnmarshalJSONFrom(dec *jsontext.Decoder) error {
+	return s.caster().UnmarshalJSONFrom(dec)
+}
+
 // String returns a readable JSON representation of the shape
 func (s CircleShape) String() string { return (&s).caster().String() }
 
@@ -260,6 +275,16 @@ func (s CircleShape) MarshalJSON() ([]byte, error) { return (&s).caster().Marsha
 // UnmarshalJSON unmarshals JSON data to the CircleShape
 func (s *CircleShape) UnmarshalJSON(data []byte) error { return s.caster().UnmarshalJSON(data) }
 
+// MarshalJSONTo marshals the CircleShape to enc
+func (s CircleShape) MarshalJSONTo(enc *jsontext.Encoder) error {
+	return (&s).caster().MarshalJSONTo(enc)
+}
+
+// UnmarshalJSONFrom unmarshals the CircleShape from dec
+func (s *CircleShape) UnmarshalJSONFrom(dec *jsontext.Decoder) error {
+	return s.caster().UnmarshalJSONFrom(dec)
+}
+
 // String returns a readable JSON representation of the shape
 func (s RectangleShape) String() string { return (&s).caster().String() }
 
@@ -269,6 +294,16 @@ func (s RectangleShape) MarshalJSON() ([]byte, error) { return (&s).caster().Mar
 // UnmarshalJSON unmarshals JSON data to the RectangleShape
 func (s *RectangleShape) UnmarshalJSON(data []byte) error { return s.caster().UnmarshalJSON(data) }
 
+// MarshalJSONTo marshals the RectangleShape to enc
+func (s RectangleShape) MarshalJSONTo(enc *jsontext.Encoder) error {
+	return (&s).caster().MarshalJSONTo(enc)
+}
+
+// UnmarshalJSONFrom unmarshals the RectangleShape from dec
+func (s *RectangleShape) UnmarshalJSONFrom(dec *jsontext.Decoder) error {
+	return s.caster().UnmarshalJSONFrom(dec)
+}
+
 // RULES: Methods that cast a pointer from 1 type to another, require by-ref receiver (XxxCaster methods).
 
 // caster returns shapeCaster's underlyting sumtype.Caster to access its helper methods.
@@ -279,11 +314,11 @@ func (c *shapeCaster) json() *shape { return c.caster().Json() }
 
 // ensureKind ensures that the current shape kind matches the specified kind; it panics if not.
 func (c *shapeCaster) ensureKind(kind ShapeKind) {
-	if c.json().Kind == nil {
-		panic(fmt.Sprintf("can't cast shape from Kind=nil to Kind=%s", kind))
-	}
-	if *c.json().Kind != kind {
-		panic(fmt.Sprintf("can't cast shape from Kind=%v to Kind=%s", *c.json().Kind, kind))
+	switch {
+	case c.json().Kind == nil:
+		panic(sumtype.ErrNilKind)
+	case *c.json().Kind != kind:
+		panic(&sumtype.KindMismatchError{Have: *c.json().Kind, Want: kind})
 	}
 }
 
@@ -302,20 +337,24 @@ func (c *shapeCaster) Rectangle() *RectangleShape {
 	return sumtype.Cast[RectangleShape](c.caster())
 }
 
-// SetCircle casts any *XxxShape to a *CircleShape
+// TryCircle casts any *XxxShape to a *CircleShape; it returns an error if Kind != CircleShapeKind.
+func (c *shapeCaster) TryCircle() (*CircleShape, error) {
+	return sumtype.TryCast[CircleShape](c.caster(), CircleShapeKind)
+}
+
+// TryRectangle casts any *XxxShape to a *RectangleShape; it returns an error if Kind != RectangleShapeKind.
+func (c *shapeCaster) TryRectangle() (*RectangleShape, error) {
+	return sumtype.TryCast[RectangleShape](c.caster(), RectangleShapeKind)
+}
+
+// SetCircle changes any *XxxShape's kind to circle, zeroing the fields irrelevant to it, and casts it to a *CircleShape
 func (c *shapeCaster) SetCircle() *CircleShape {
-	s := c.Shape()
-	*s.Kind = CircleShapeKind
-	c.caster().ZeroNonKindFields(s)
-	return s.Circle()
+	return sumtype.MustSetKind[CircleShape](c.caster(), CircleShapeKind)
 }
 
-// SetRectangle casts any *XxxShape to a *RectangleShape
+// SetRectangle changes any *XxxShape's kind to rectangle, zeroing the fields irrelevant to it, and casts it to a *RectangleShape
 func (c *shapeCaster) SetRectangle() *RectangleShape {
-	s := c.Shape()
-	*s.Kind = RectangleShapeKind
-	c.caster().ZeroNonKindFields(s)
-	return s.Rectangle()
+	return sumtype.MustSetKind[RectangleShape](c.caster(), RectangleShapeKind)
 }
 
 // String returns a readable JSON representation of the shape
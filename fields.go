@@ -0,0 +1,63 @@
+package sumtype
+
+import (
+	"encoding/json/jsontext"
+	"reflect"
+	"strings"
+)
+
+// jsonName returns the JSON object member name of a Json struct field; ok is false if the field
+// isn't marshaled as a named member (unexported, `json:"-"` or JSON embedded fields).
+func jsonName(f reflect.StructField) (name string, ok bool) {
+	if !f.IsExported() || hasJSONOption(f, "embed") {
+		return "", false
+	}
+	tag, hasTag := f.Tag.Lookup("json")
+	if tag == "-" {
+		return "", false
+	}
+	name, _, _ = strings.Cut(tag, ",")
+	if !hasTag || name == "" {
+		name = f.Name
+	}
+	return strings.Trim(name, "'"), true
+}
+
+// hasJSONOption returns true if the field's json tag has the specified option (ex: embed).
+func hasJSONOption(f reflect.StructField, option string) bool {
+	_, options, _ := strings.Cut(f.Tag.Get("json"), ",")
+	for o := range strings.SplitSeq(options, ",") {
+		if o == option {
+			return true
+		}
+	}
+	return false
+}
+
+// hiddenFields returns the indexes of Json's exported fields that projection doesn't export;
+// these are the fields irrelevant to the projection's kind. The embedded fallback field capturing
+// unknown JSON members is never hidden since unknown members don't belong to any particular kind.
+func hiddenFields(json, projection reflect.Type) []int {
+	var hidden []int
+	for f := range json.NumField() {
+		if jf := json.Field(f); jf.IsExported() && !isFallback(jf) && !projection.Field(f).IsExported() {
+			hidden = append(hidden, f)
+		}
+	}
+	return hidden
+}
+
+// isFallback returns true if f is a JSON "embedded fallback" field which captures the JSON
+// object members not handled by other fields: a `json:",embed"` field whose type is
+// jsontext.Value or map[~string]T (or a pointer to either). Only a jsontext.Value preserves the
+// members' original order.
+func isFallback(f reflect.StructField) bool {
+	if !hasJSONOption(f, "embed") {
+		return false
+	}
+	t := f.Type
+	if t.Kind() == reflect.Pointer && t.Name() == "" {
+		t = t.Elem()
+	}
+	return t == reflect.TypeFor[jsontext.Value]() || (t.Kind() == reflect.Map && t.Key().Kind() == reflect.String)
+}
@@ -0,0 +1,112 @@
+package sumtype
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"unsafe"
+)
+
+// Case handles the kinds of a sum type whose projection is a specific type; create Cases with
+// On and Default and pass them to Match.
+type Case[R any] struct {
+	projection reflect.Type           // projection is the type the handler expects
+	isDefault  bool                   // isDefault is true for a Case created by Default
+	handle     func(unsafe.Pointer) R // handle casts the Json pointer and calls the handler
+}
+
+// On returns a Case that calls handler with the sum type cast to *To when the current kind's
+// registered projection is To.
+func On[To any, R any](handler func(*To) R) Case[R] {
+	handle := func(p unsafe.Pointer) R { return handler((*To)(p)) }
+	return Case[R]{projection: reflect.TypeFor[To](), handle: handle}
+}
+
+// Default returns a Case that calls handler with the sum type cast to *To (typically the common
+// projection) when the current kind is nil or isn't registered (and there's no fallback
+// projection). This happens when a service returns a kind that didn't exist when the client
+// code was written. To must be the Json struct or a projection validated for it by
+// ValidateStructFields or Register.
+func Default[To any, R any](handler func(*To) R) Case[R] {
+	handle := func(p unsafe.Pointer) R { return handler((*To)(p)) }
+	return Case[R]{projection: reflect.TypeFor[To](), isDefault: true, handle: handle}
+}
+
+// Match calls the Case whose projection matches c's current kind and returns its result. Cases
+// must include an On for every registered kind's projection (and the fallback projection, if
+// any) and may include one Default. Match returns an error if Json isn't registered, if cases
+// are incomplete or ambiguous, or if there's no projection for the current kind and no Default
+// (ErrNilKind or an *UnknownKindError).
+func Match[R any, Json any](c *Caster[Json], cases ...Case[R]) (R, error) {
+	var zero R
+	r, err := registrationFor[Json]()
+	if err != nil {
+		return zero, err
+	}
+	handlers, def, err := caseHandlers(r, reflect.TypeFor[Json](), cases)
+	if err != nil {
+		return zero, err
+	}
+	return dispatch(r, reflect.ValueOf(c.Json()).Elem(), handlers, def)
+}
+
+// dispatch calls the handler for json's current kind (or def) with json's address and returns its
+// result. It returns ErrNilKind or an *UnknownKindError if there's no handler.
+func dispatch[R any](r *registration, json reflect.Value,
+	handlers map[reflect.Type]func(unsafe.Pointer) R, def func(unsafe.Pointer) R) (R, error) {
+	var zero R
+	kind, ok := r.kind(json)
+	if projection, ok := r.projection(kind, ok); ok {
+		return handlers[projection](json.Addr().UnsafePointer()), nil
+	}
+	switch {
+	case def != nil:
+		return def(json.Addr().UnsafePointer()), nil
+	case !ok:
+		return zero, ErrNilKind
+	default:
+		return zero, &UnknownKindError{Kind: kind}
+	}
+}
+
+// caseHandlers returns cases' handlers by projection type and the Default handler (or nil). It
+// returns an error if a case isn't for json or a projection validated for it, if a registered
+// projection has no Case, or if cases has duplicates or Cases for unregistered projections.
+func caseHandlers[R any](r *registration, json reflect.Type,
+	cases []Case[R]) (map[reflect.Type]func(unsafe.Pointer) R, func(unsafe.Pointer) R, error) {
+	handlers, def := map[reflect.Type]func(unsafe.Pointer) R{}, (func(unsafe.Pointer) R)(nil)
+	for _, c := range cases {
+		switch _, dup := handlers[c.projection]; {
+		case c.isDefault && def != nil:
+			return nil, nil, errors.New("sumtype: multiple Default cases")
+		case !validatedCast(json, c.projection):
+			return nil, nil, fmt.Errorf("sumtype: case for %s which ValidateStructFields or Register didn't validate",
+				c.projection)
+		case c.isDefault:
+			def = c.handle
+		case dup:
+			return nil, nil, fmt.Errorf("sumtype: multiple cases for %s", c.projection)
+		default:
+			handlers[c.projection] = c.handle
+		}
+	}
+
+	registered := map[reflect.Type]bool{}
+	for _, kind := range r.kinds {
+		projection := r.projections[kind]
+		if registered[projection] = true; handlers[projection] == nil {
+			return nil, nil, fmt.Errorf("sumtype: no case for kind %v (%s)", kind, projection)
+		}
+	}
+	if r.fallback != nil {
+		if registered[r.fallback] = true; handlers[r.fallback] == nil {
+			return nil, nil, fmt.Errorf("sumtype: no case for unknown kinds (%s)", r.fallback)
+		}
+	}
+	for projection := range handlers {
+		if !registered[projection] {
+			return nil, nil, fmt.Errorf("sumtype: case for %s which isn't a registered projection", projection)
+		}
+	}
+	return handlers, def, nil
+}
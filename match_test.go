@@ -0,0 +1,61 @@
+package sumtype_test
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/JeffreyRichter/sumtype"
+)
+
+// describe returns a description of any shape using a Match with a case per kind
+func describe(s *Shape) (string, error) {
+	return sumtype.Match(s.caster(),
+		sumtype.On(func(c *CircleShape) string { return fmt.Sprintf("circle r=%d", *c.Radius) }),
+		sumtype.On(func(r *RectangleShape) string { return fmt.Sprintf("rectangle %dx%d", *r.Width, *r.Height) }),
+		sumtype.Default(func(s *Shape) string { return fmt.Sprintf("unknown %v", s.caster().Kind()) }),
+	)
+}
+
+// TestMatch tests that Match calls the handler for the current kind with the typed projection
+func TestMatch(t *testing.T) {
+	tests := map[string]*Shape{
+		"circle r=2":       (&CircleShape{Kind: ptr(CircleShapeKind), Radius: ptr(2)}).Shape(),
+		"rectangle 3x4":    (&RectangleShape{Kind: ptr(RectangleShapeKind), Width: ptr(3), Height: ptr(4)}).Shape(),
+		"unknown triangle": {Kind: ptr[ShapeKind]("triangle")},
+		"unknown <nil>":    {},
+	}
+	for want, s := range tests {
+		if got, err := describe(s); err != nil || got != want {
+			t.Errorf("Expected %q, got %q, %v", want, got, err)
+		}
+	}
+}
+
+// TestMatchErrors tests that Match rejects incomplete or ambiguous cases
+func TestMatchErrors(t *testing.T) {
+	circle := sumtype.On(func(c *CircleShape) int { return 1 })
+	rectangle := sumtype.On(func(r *RectangleShape) int { return 2 })
+	def := sumtype.Default(func(s *Shape) int { return 0 })
+	s := (&CircleShape{Kind: ptr(CircleShapeKind)}).Shape()
+
+	tests := map[string][]sumtype.Case[int]{
+		"no case for kind rectangle": {circle, def},
+		"multiple cases":             {circle, rectangle, circle},
+		"multiple Default":           {circle, rectangle, def, def},
+		"isn't a registered":         {circle, rectangle, sumtype.On(func(s *Shape) int { return 0 })},
+		"didn't validate":            {circle, rectangle, sumtype.Default(func(*[64]int) int { return 0 })},
+		"case for [64]int which":     {circle, rectangle, sumtype.On(func(*[64]int) int { return 0 })},
+	}
+	for want, cases := range tests {
+		if _, err := sumtype.Match(s.caster(), cases...); err == nil || !strings.Contains(err.Error(), want) {
+			t.Errorf("Expected an error containing %q, got %v", want, err)
+		}
+	}
+
+	var none Shape
+	if _, err := sumtype.Match(none.caster(), circle, rectangle); !errors.Is(err, sumtype.ErrNilKind) {
+		t.Errorf("Expected ErrNilKind, got %v", err)
+	}
+}
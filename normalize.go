@@ -0,0 +1,26 @@
+package sumtype
+
+import (
+	"encoding/json/v2"
+	"reflect"
+	"unsafe"
+)
+
+// MarshalNormalized marshals the Json struct instance to JSON emitting only the fields exported
+// by the current kind's registered projection, without modifying the instance. This gives
+// canonical output even if fields irrelevant to the kind are stale (see ZeroNonKindFields). All
+// fields are emitted if the kind is nil or unregistered. Json must be registered (see
+// Register); otherwise MarshalNormalized returns ErrNotRegistered.
+func (c *Caster[Json]) MarshalNormalized() ([]byte, error) {
+	r, err := registrationFor[Json]()
+	if err != nil {
+		return nil, err
+	}
+	normalized := *c.Json() // Zero the fields of a shallow copy
+	if kind, ok := r.kind(reflect.ValueOf(&normalized).Elem()); ok {
+		if projection, ok := r.projections[kind]; ok {
+			zeroPlanFor(reflect.TypeFor[Json](), projection).zero(unsafe.Pointer(&normalized))
+		}
+	}
+	return json.Marshal(&normalized)
+}
@@ -0,0 +1,45 @@
+package sumtype_test
+
+import "testing"
+
+// TestMarshalNormalized tests that normalized marshaling drops fields irrelevant to the kind
+func TestMarshalNormalized(t *testing.T) {
+	tests := []struct {
+		name string
+		s    *shape
+		want string
+	}{
+		{
+			name: "Circle with stale width",
+			s:    &shape{Kind: ptr(CircleShapeKind), Color: ptr("red"), Radius: ptr(1), Width: ptr(5)},
+			want: `{"color":"red","kind":"circle","radius":1}`,
+		},
+		{
+			name: "Rectangle with stale radius",
+			s:    &shape{Kind: ptr(RectangleShapeKind), Radius: ptr(1), Width: ptr(5), Height: ptr(6)},
+			want: `{"kind":"rectangle","width":5,"height":6}`,
+		},
+		{
+			name: "Unknown kind keeps everything",
+			s:    &shape{Kind: ptr[ShapeKind]("triangle"), Radius: ptr(1), Width: ptr(5)},
+			want: `{"kind":"triangle","radius":1,"width":5}`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.s.caster().MarshalNormalized()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(got) != tt.want {
+				t.Errorf("Expected %s, got %s", tt.want, got)
+			}
+		})
+	}
+
+	// The instance itself must not be modified
+	s := &shape{Kind: ptr(CircleShapeKind), Width: ptr(5)}
+	if _, err := s.caster().MarshalNormalized(); err != nil || s.Width == nil {
+		t.Errorf("MarshalNormalized modified the instance: %v", err)
+	}
+}
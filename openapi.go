@@ -0,0 +1,53 @@
+package sumtype
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// openAPIRef is the prefix of references to OpenAPI component schemas.
+const openAPIRef = "#/components/schemas/"
+
+// OpenAPISchemas returns OpenAPI 3.1 components.schemas entries for registered Json. common is
+// the public projection exposing the fields shared by all kinds (pass a zero value like
+// Shape{}). The common projection's schema has the shared properties and a discriminator
+// mapping each kind to its projection's schema. Each projection's schema is an allOf
+// referencing the common schema plus the projection's kind-specific properties. Schemas are
+// named after their projection types. Json must be registered (see Register); otherwise
+// OpenAPISchemas returns ErrNotRegistered.
+func OpenAPISchemas[Json any](common any) (map[string]*Schema, error) {
+	r, err := registrationFor[Json]()
+	if err != nil {
+		return nil, err
+	}
+	if err := (Caster[Json]{}).validateStructFields(common); err != nil {
+		return nil, err
+	}
+	jsonType, commonType := reflect.TypeFor[Json](), reflect.TypeOf(common)
+	kindName, ok := jsonName(jsonType.Field(r.kindField))
+	if !ok || !commonType.Field(r.kindField).IsExported() {
+		return nil, fmt.Errorf("the discriminator must be a JSON member exported by %s", commonType.Name())
+	}
+
+	base := &Schema{Type: "object", Properties: map[string]*Schema{}, Required: []string{kindName},
+		Discriminator: &Discriminator{PropertyName: kindName, Mapping: map[string]string{}}}
+	schemas := map[string]*Schema{commonType.Name(): base}
+	for f := range jsonType.NumField() {
+		if name, ok := jsonName(jsonType.Field(f)); ok && commonType.Field(f).IsExported() {
+			base.Properties[name] = typeSchema(jsonType.Field(f).Type, map[reflect.Type]bool{})
+		}
+	}
+
+	for _, kind := range r.kinds {
+		projection := r.projections[kind]
+		base.Discriminator.Mapping[fmt.Sprint(kind)] = openAPIRef + projection.Name()
+		specific := &Schema{Type: "object", Properties: map[string]*Schema{}}
+		for f := range jsonType.NumField() {
+			if name, ok := jsonName(jsonType.Field(f)); ok && projection.Field(f).IsExported() && !commonType.Field(f).IsExported() {
+				specific.Properties[name] = typeSchema(jsonType.Field(f).Type, map[reflect.Type]bool{})
+			}
+		}
+		schemas[projection.Name()] = &Schema{AllOf: []*Schema{{Ref: openAPIRef + commonType.Name()}, specific}}
+	}
+	return schemas, nil
+}
@@ -0,0 +1,42 @@
+package sumtype_test
+
+import (
+	"encoding/json/jsontext"
+	"encoding/json/v2"
+	"errors"
+	"os"
+	"testing"
+
+	"github.com/JeffreyRichter/sumtype"
+)
+
+// TestOpenAPISchemas tests the OpenAPI components generated for the registered shape sum type
+func TestOpenAPISchemas(t *testing.T) {
+	schemas, err := sumtype.OpenAPISchemas[shape](Shape{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	doc := map[string]any{"components": map[string]any{"schemas": schemas}}
+	got, err := json.Marshal(doc, json.Deterministic(true), jsontext.WithIndent("  "))
+	if err != nil {
+		t.Fatal(err)
+	}
+	// sumtypegen -openapi must describe the same schemas as the library
+	want, err := os.ReadFile("cmd/sumtypegen/testdata/shape_openapi.golden")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(got) != string(want) {
+		t.Errorf("Expected:\n%s\ngot:\n%s", want, got)
+	}
+}
+
+// TestOpenAPISchemasErrors tests that OpenAPISchemas rejects unregistered types and bad common projections
+func TestOpenAPISchemasErrors(t *testing.T) {
+	if _, err := sumtype.OpenAPISchemas[struct{ A int }](Shape{}); !errors.Is(err, sumtype.ErrNotRegistered) {
+		t.Errorf("Expected ErrNotRegistered, got %v", err)
+	}
+	if _, err := sumtype.OpenAPISchemas[shape](struct{ A int }{}); err == nil {
+		t.Error("Expected an error for a mismatched common projection")
+	}
+}
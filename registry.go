@@ -0,0 +1,170 @@
+package sumtype
+
+import (
+	"cmp"
+	"fmt"
+	"maps"
+	"reflect"
+	"slices"
+	"sync"
+	"unsafe"
+)
+
+// registration records a sum type's discriminator field and the projection type of each kind.
+type registration struct {
+	kindField   int                  // kindField is the index of Json's discriminator field
+	kindOffset  uintptr              // kindOffset is the offset of Json's discriminator field
+	kindIsPtr   bool                 // kindIsPtr is true if the discriminator field is a *Kind
+	kinds       []any                // kinds are the registered discriminator values in sorted order
+	projections map[any]reflect.Type // projections maps each kind to its projection struct type
+	plans       map[any]zeroPlan     // plans maps each kind to the plan zeroing its irrelevant fields
+	policy      UnknownKindPolicy    // policy specifies how unregistered kinds are handled
+	fallback    reflect.Type         // fallback is the projection for unregistered kinds (UnknownKindFallback)
+	transitions any                  // transitions is the Transitions[Json, Kind] set by SetTransitions or nil
+}
+
+// registrations maps a Json struct's reflect.Type to its *registration.
+var registrations sync.Map
+
+// registrationFor returns Json's registration; it returns ErrNotRegistered if Json was never
+// registered.
+func registrationFor[Json any]() (*registration, error) {
+	if r, ok := registrations.Load(reflect.TypeFor[Json]()); ok {
+		return r.(*registration), nil
+	}
+	return nil, fmt.Errorf("%w: %s", ErrNotRegistered, reflect.TypeFor[Json]())
+}
+
+// update replaces Json's registration with a modified copy so concurrent readers never observe a
+// partially updated registration. It returns ErrNotRegistered or modify's error.
+func update[Json any](modify func(r *registration) error) error {
+	for {
+		old, err := registrationFor[Json]()
+		if err != nil {
+			return err
+		}
+		r := *old
+		if err := modify(&r); err != nil {
+			return err
+		}
+		if registrations.CompareAndSwap(reflect.TypeFor[Json](), old, &r) {
+			return nil
+		}
+	}
+}
+
+// Register records that Json's discriminator is its exported kindFieldName field (of type Kind
+// or *Kind) and maps each kind to its projection struct (pass a zero value like CircleShape{}).
+// Registering also validates the projections like ValidateStructFields. A Json type can be
+// registered only once. If panicOnError is true, Register panics if there is an error,
+// otherwise it returns the error (or nil if no error).
+func Register[Json any, Kind cmp.Ordered](panicOnError bool, kindFieldName string, kinds map[Kind]any) error {
+	err := register[Json](kindFieldName, kinds)
+	if panicOnError && err != nil {
+		panic(err)
+	}
+	return err
+}
+
+// register validates and stores Json's registration. It returns nil or an error.
+func register[Json any, Kind cmp.Ordered](kindFieldName string, kinds map[Kind]any) error {
+	jsonType, kindType := reflect.TypeFor[Json](), reflect.TypeFor[Kind]()
+	if jsonType.Kind() != reflect.Struct {
+		return fmt.Errorf("%s is not a struct", jsonType)
+	}
+	kindField, ok := jsonType.FieldByName(kindFieldName)
+	if !ok || !kindField.IsExported() || len(kindField.Index) != 1 {
+		return fmt.Errorf("struct %s has no exported field %s", jsonType.Name(), kindFieldName)
+	}
+	r := &registration{kindField: kindField.Index[0], kindOffset: kindField.Offset,
+		projections: map[any]reflect.Type{}}
+	switch kindField.Type {
+	case kindType:
+	case reflect.PointerTo(kindType):
+		r.kindIsPtr = true
+	default:
+		return fmt.Errorf("field %s.%s must be of type %s or *%s", jsonType.Name(), kindFieldName, kindType, kindType)
+	}
+
+	structs := []any{}
+	for _, kind := range slices.Sorted(maps.Keys(kinds)) {
+		projection := reflect.TypeOf(kinds[kind])
+		if projection == nil || projection.Kind() != reflect.Struct {
+			return fmt.Errorf("projection for kind %v must be a struct value, not %T", kind, kinds[kind])
+		}
+		r.kinds, r.projections[kind] = append(r.kinds, kind), projection
+		structs = append(structs, kinds[kind])
+	}
+	if err := (Caster[Json]{}).validateStructFields(structs...); err != nil {
+		return err
+	}
+	r.plans = map[any]zeroPlan{}
+	for kind, projection := range r.projections {
+		r.plans[kind] = zeroPlanFor(jsonType, projection)
+	}
+	if _, loaded := registrations.LoadOrStore(jsonType, r); loaded {
+		return fmt.Errorf("struct %s is already registered", jsonType.Name())
+	}
+	return nil
+}
+
+// Kinds returns Json's registered discriminator values in sorted order; it returns nil if Json
+// isn't registered.
+func Kinds[Json any]() []any {
+	if r, err := registrationFor[Json](); err == nil {
+		return slices.Clone(r.kinds)
+	}
+	return nil
+}
+
+// kind returns the Json struct's current discriminator value; ok is false if the discriminator
+// is a nil pointer.
+func (r *registration) kind(json reflect.Value) (kind any, ok bool) {
+	field := json.Field(r.kindField)
+	if r.kindIsPtr {
+		if field.IsNil() {
+			return nil, false
+		}
+		field = field.Elem()
+	}
+	return field.Interface(), true
+}
+
+// projection returns the projection for a kind returned by kind (along with its ok value). For
+// a nil kind, it returns false. For an unregistered kind, it returns the fallback projection
+// (see UnknownKindFallback) or false if there isn't one.
+func (r *registration) projection(kind any, ok bool) (reflect.Type, bool) {
+	if !ok {
+		return nil, false
+	}
+	if projection, ok := r.projections[kind]; ok {
+		return projection, true
+	}
+	return r.fallback, r.fallback != nil
+}
+
+// Kind returns the current discriminator value; it returns nil if the discriminator is a nil
+// pointer or if Json isn't registered.
+func (c *Caster[Json]) Kind() any {
+	if r, err := registrationFor[Json](); err == nil {
+		kind, _ := r.kind(reflect.ValueOf(c.Json()).Elem())
+		return kind
+	}
+	return nil
+}
+
+// Variant casts c to a pointer to the projection registered for the current kind (for example,
+// a *CircleShape) and returns it as an any suitable for a type switch. For an unregistered kind,
+// it returns the fallback projection if the policy is UnknownKindFallback. It returns nil if
+// Json isn't registered or if there is no projection for the current kind.
+func (c *Caster[Json]) Variant() any {
+	r, err := registrationFor[Json]()
+	if err != nil {
+		return nil
+	}
+	projection, ok := r.projection(r.kind(reflect.ValueOf(c.Json()).Elem()))
+	if !ok {
+		return nil
+	}
+	return reflect.NewAt(projection, unsafe.Pointer(c)).Interface()
+}
@@ -0,0 +1,78 @@
+package sumtype_test
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/JeffreyRichter/sumtype"
+)
+
+// TestKinds tests enumerating a registered sum type's kinds
+func TestKinds(t *testing.T) {
+	if kinds := sumtype.Kinds[shape](); !slices.Equal(kinds, []any{CircleShapeKind, RectangleShapeKind}) {
+		t.Errorf("Unexpected kinds: %v", kinds)
+	}
+	if kinds := sumtype.Kinds[struct{}](); kinds != nil {
+		t.Errorf("Expected no kinds for an unregistered type, got %v", kinds)
+	}
+}
+
+// TestKindAndVariant tests getting the current kind and its projection from the registry
+func TestKindAndVariant(t *testing.T) {
+	s := (&CircleShape{Kind: ptr(CircleShapeKind), Radius: ptr(5)}).Shape()
+	if kind := s.caster().Kind(); kind != CircleShapeKind {
+		t.Errorf("Expected kind %s, got %v", CircleShapeKind, kind)
+	}
+	switch v := s.caster().Variant().(type) {
+	case *CircleShape:
+		if v != s.Circle() || *v.Radius != 5 {
+			t.Error("Variant doesn't point to the same memory")
+		}
+	default:
+		t.Errorf("Expected *CircleShape, got %T", v)
+	}
+
+	r := s.SetRectangle()
+	if _, ok := r.caster().Variant().(*RectangleShape); !ok {
+		t.Errorf("Expected *RectangleShape, got %T", r.caster().Variant())
+	}
+
+	unknown := &Shape{Kind: ptr[ShapeKind]("triangle")}
+	if v := unknown.caster().Variant(); v != nil {
+		t.Errorf("Expected nil variant for an unregistered kind, got %T", v)
+	}
+	var none Shape
+	if none.caster().Kind() != nil || none.caster().Variant() != nil {
+		t.Error("Expected nil kind and variant for a nil Kind")
+	}
+}
+
+// TestRegisterErrors tests that invalid registrations are rejected
+func TestRegisterErrors(t *testing.T) {
+	tests := map[string]func() error{
+		"duplicate": func() error {
+			return sumtype.Register[shape](false, "Kind", map[ShapeKind]any{CircleShapeKind: CircleShape{}})
+		},
+		"missing field": func() error {
+			return sumtype.Register[shape](false, "Shape", map[ShapeKind]any{CircleShapeKind: CircleShape{}})
+		},
+		"wrong kind type": func() error {
+			return sumtype.Register[shape](false, "Kind", map[string]any{"circle": CircleShape{}})
+		},
+		"pointer projection": func() error {
+			return sumtype.Register[shape](false, "Kind", map[ShapeKind]any{CircleShapeKind: &CircleShape{}})
+		},
+		"mismatched projection": func() error {
+			return sumtype.Register[shape](false, "Kind", map[ShapeKind]any{CircleShapeKind: struct{ X int }{}})
+		},
+	}
+	for name, register := range tests {
+		t.Run(name, func(t *testing.T) {
+			if err := register(); err == nil {
+				t.Error("Expected an error")
+			} else {
+				t.Log(err)
+			}
+		})
+	}
+}
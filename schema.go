@@ -0,0 +1,119 @@
+package sumtype
+
+import (
+	"encoding/json/jsontext"
+	"reflect"
+	"time"
+)
+
+// Schema is a JSON Schema (draft 2020-12) schema object, which is also an OpenAPI 3.1 schema
+// object. Marshal it with json.Deterministic(true) to get properties in a stable (sorted) order.
+type Schema struct {
+	Schema               string             `json:"$schema,omitempty"`
+	Ref                  string             `json:"$ref,omitempty"`
+	Title                string             `json:"title,omitempty"`
+	Description          string             `json:"description,omitempty"`
+	Type                 string             `json:"type,omitempty"`
+	Format               string             `json:"format,omitempty"`
+	ContentEncoding      string             `json:"contentEncoding,omitempty"`
+	Const                any                `json:"const,omitzero"`
+	Properties           map[string]*Schema `json:"properties,omitempty"`
+	Required             []string           `json:"required,omitempty"`
+	Items                *Schema            `json:"items,omitempty"`
+	AdditionalProperties *Schema            `json:"additionalProperties,omitempty"`
+	OneOf                []*Schema          `json:"oneOf,omitempty"`
+	AllOf                []*Schema          `json:"allOf,omitempty"`
+	Discriminator        *Discriminator     `json:"discriminator,omitempty"`
+}
+
+// Discriminator is an OpenAPI discriminator object mapping discriminator values to schemas.
+type Discriminator struct {
+	PropertyName string            `json:"propertyName"`
+	Mapping      map[string]string `json:"mapping,omitempty"`
+}
+
+// draft202012 is the JSON Schema dialect of the schemas returned by JSONSchema.
+const draft202012 = "https://json-schema.org/draft/2020-12/schema"
+
+// JSONSchema returns a JSON Schema for registered Json. The schema is a oneOf with a subschema
+// per kind (in sorted order) whose discriminator property is a const of the kind. Each
+// subschema's properties are the Json fields exported by the kind's projection, named by the
+// Json struct's json tags. Json must be registered (see Register); otherwise JSONSchema returns
+// ErrNotRegistered.
+func JSONSchema[Json any]() (*Schema, error) {
+	r, err := registrationFor[Json]()
+	if err != nil {
+		return nil, err
+	}
+	jsonType := reflect.TypeFor[Json]()
+	schema := &Schema{Schema: draft202012, Title: jsonType.Name()}
+	for _, kind := range r.kinds {
+		schema.OneOf = append(schema.OneOf, r.kindSchema(jsonType, r.projections[kind], kind))
+	}
+	return schema, nil
+}
+
+// kindSchema returns the object schema for projection with the discriminator's const set to kind.
+func (r *registration) kindSchema(jsonType, projection reflect.Type, kind any) *Schema {
+	schema := &Schema{Title: projection.Name(), Type: "object", Properties: map[string]*Schema{}}
+	for f := range jsonType.NumField() {
+		name, ok := jsonName(jsonType.Field(f))
+		if !ok || !projection.Field(f).IsExported() {
+			continue
+		}
+		if f == r.kindField {
+			schema.Properties[name] = &Schema{Const: kind}
+			schema.Required = append(schema.Required, name)
+			continue
+		}
+		schema.Properties[name] = typeSchema(jsonType.Field(f).Type, map[reflect.Type]bool{})
+	}
+	return schema
+}
+
+// typeSchema returns the schema for values of type t as marshaled by encoding/json/v2. visiting
+// contains the struct types being described to stop recursion on recursive types.
+func typeSchema(t reflect.Type, visiting map[reflect.Type]bool) *Schema {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	switch t {
+	case reflect.TypeFor[time.Time]():
+		return &Schema{Type: "string", Format: "date-time"}
+	case reflect.TypeFor[jsontext.Value]():
+		return &Schema{}
+	}
+
+	switch t.Kind() {
+	case reflect.String:
+		return &Schema{Type: "string"}
+	case reflect.Bool:
+		return &Schema{Type: "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return &Schema{Type: "integer"}
+	case reflect.Float32, reflect.Float64:
+		return &Schema{Type: "number"}
+	case reflect.Slice, reflect.Array:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return &Schema{Type: "string", ContentEncoding: "base64"}
+		}
+		return &Schema{Type: "array", Items: typeSchema(t.Elem(), visiting)}
+	case reflect.Map:
+		return &Schema{Type: "object", AdditionalProperties: typeSchema(t.Elem(), visiting)}
+	case reflect.Struct:
+		if visiting[t] {
+			return &Schema{Type: "object"}
+		}
+		visiting[t] = true
+		defer delete(visiting, t)
+		schema := &Schema{Type: "object", Properties: map[string]*Schema{}}
+		for f := range t.NumField() {
+			if name, ok := jsonName(t.Field(f)); ok {
+				schema.Properties[name] = typeSchema(t.Field(f).Type, visiting)
+			}
+		}
+		return schema
+	}
+	return &Schema{} // Any JSON value
+}
@@ -0,0 +1,87 @@
+package sumtype_test
+
+import (
+	"encoding/json/jsontext"
+	"encoding/json/v2"
+	"testing"
+
+	"github.com/JeffreyRichter/sumtype"
+)
+
+// TestJSONSchema tests the JSON Schema generated for the registered shape sum type
+func TestJSONSchema(t *testing.T) {
+	schema, err := sumtype.JSONSchema[shape]()
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := json.Marshal(schema, json.Deterministic(true), jsontext.WithIndent("  "))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `{
+  "$schema": "https://json-schema.org/draft/2020-12/schema",
+  "title": "shape",
+  "oneOf": [
+    {
+      "title": "CircleShape",
+      "type": "object",
+      "properties": {
+        "color": {
+          "type": "string"
+        },
+        "kind": {
+          "const": "circle"
+        },
+        "radius": {
+          "type": "integer"
+        }
+      },
+      "required": [
+        "kind"
+      ]
+    },
+    {
+      "title": "RectangleShape",
+      "type": "object",
+      "properties": {
+        "color": {
+          "type": "string"
+        },
+        "height": {
+          "type": "integer"
+        },
+        "kind": {
+          "const": "rectangle"
+        },
+        "width": {
+          "type": "integer"
+        }
+      },
+      "required": [
+        "kind"
+      ]
+    }
+  ]
+}`
+	if string(got) != want {
+		t.Errorf("Unexpected schema:\n%s", got)
+	}
+}
+
+// TestJSONSchemaTypes tests the schemas of the Go types a Json struct's fields may have
+func TestJSONSchemaTypes(t *testing.T) {
+	schema, err := sumtype.JSONSchema[pet]()
+	if err != nil {
+		t.Fatal(err)
+	}
+	dog := schema.OneOf[1] // Kinds are sorted: cat, dog
+	if dog.Title != "Dog" || dog.Properties["barks"].Type != "boolean" {
+		t.Errorf("Unexpected dog schema: %+v", dog)
+	}
+	if _, ok := dog.Properties["Unknown"]; ok {
+		t.Error("The unknown members field must not be a property")
+	}
+	if _, err := sumtype.JSONSchema[struct{}](); err == nil {
+		t.Error("Expected an error for an unregistered type")
+	}
+}
@@ -0,0 +1,68 @@
+package sumtype
+
+import (
+	"encoding/json/jsontext"
+	"fmt"
+	"io"
+	"iter"
+	"reflect"
+	"unsafe"
+)
+
+// DecodeSeq returns an iterator that streams the elements of the JSON array read from r, decoding
+// each to a new *Json with UnmarshalWith so arrays too large to fit in memory can be processed one
+// element at a time. If cases are passed, each decoded element is also dispatched to the Case for
+// its kind (see Match) and the handler's error is yielded with the element.
+//
+// An element that fails to unmarshal (or whose kind Json's UnknownKindPolicy rejects) is yielded
+// with its error and iteration continues with the next element. Iteration ends after yielding a
+// malformed JSON or non-array input error, or an error because cases were passed but Json isn't
+// registered or cases are incomplete or ambiguous (the *Json is nil for these).
+func DecodeSeq[Json any](r io.Reader, cases ...Case[error]) iter.Seq2[*Json, error] {
+	return func(yield func(*Json, error) bool) {
+		var reg *registration
+		var handlers map[reflect.Type]func(unsafe.Pointer) error
+		var def func(unsafe.Pointer) error
+		if len(cases) > 0 {
+			var err error
+			if reg, err = registrationFor[Json](); err != nil {
+				yield(nil, err)
+				return
+			}
+			if handlers, def, err = caseHandlers(reg, reflect.TypeFor[Json](), cases); err != nil {
+				yield(nil, err)
+				return
+			}
+		}
+
+		dec := jsontext.NewDecoder(r)
+		if tok, err := dec.ReadToken(); err != nil || tok.Kind() != '[' {
+			if err == nil {
+				err = fmt.Errorf("sumtype: DecodeSeq expects a JSON array, not %v", tok.Kind())
+			}
+			yield(nil, err)
+			return
+		}
+		for dec.PeekKind() != ']' {
+			value, err := dec.ReadValue() // Syntax errors leave dec unusable so they end iteration
+			if err != nil {
+				yield(nil, err)
+				return
+			}
+			j := new(Json)
+			err = (*Caster[Json])(unsafe.Pointer(j)).UnmarshalWith(value)
+			if err == nil && reg != nil {
+				var handlerErr error
+				if handlerErr, err = dispatch(reg, reflect.ValueOf(j).Elem(), handlers, def); err == nil {
+					err = handlerErr
+				}
+			}
+			if !yield(j, err) {
+				return
+			}
+		}
+		if _, err := dec.ReadToken(); err != nil { // Read the closing ']'
+			yield(nil, err)
+		}
+	}
+}
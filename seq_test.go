@@ -0,0 +1,85 @@
+package sumtype_test
+
+import (
+	"errors"
+	"fmt"
+	"iter"
+	"strings"
+	"testing"
+
+	"github.com/JeffreyRichter/sumtype"
+)
+
+// TestDecodeSeq tests that DecodeSeq streams an array's shapes and dispatches each by kind
+func TestDecodeSeq(t *testing.T) {
+	in := `[{"kind":"circle","radius":1}, {"kind":"rectangle","width":2,"height":3}, {"kind":"circle","radius":"x"}, {"kind":"triangle"}]`
+	var got []string
+	record := func(s string) error { got = append(got, s); return nil }
+	errFailed := errors.New("failed")
+	var errs []error
+	for s, err := range sumtype.DecodeSeq[shape](strings.NewReader(in),
+		sumtype.On(func(c *CircleShape) error { return record(fmt.Sprintf("circle r=%d", *c.Radius)) }),
+		sumtype.On(func(r *RectangleShape) error {
+			record(fmt.Sprintf("rectangle %dx%d", *r.Width, *r.Height))
+			return errFailed
+		}),
+	) {
+		if s == nil {
+			t.Fatalf("Unexpected end of iteration: %v", err)
+		}
+		errs = append(errs, err)
+	}
+
+	if want := "circle r=1, rectangle 2x3"; strings.Join(got, ", ") != want {
+		t.Errorf("Expected %q, got %q", want, got)
+	}
+	var unknown *sumtype.UnknownKindError
+	if len(errs) != 4 || errs[0] != nil || errs[1] != errFailed || errs[2] == nil || !errors.As(errs[3], &unknown) {
+		t.Errorf("Unexpected errors: %v", errs)
+	}
+}
+
+// TestDecodeSeqStop tests that DecodeSeq stops decoding when the loop breaks
+func TestDecodeSeqStop(t *testing.T) {
+	n := 0
+	for s, err := range sumtype.DecodeSeq[shape](strings.NewReader(`[{"kind":"circle"}, {"kind":"rectangle"}, !]`)) {
+		if err != nil || *s.Kind != CircleShapeKind {
+			t.Errorf("Unexpected shape %v (error: %v)", s, err)
+		}
+		if n++; n == 1 {
+			break
+		}
+	}
+}
+
+// TestDecodeSeqErrors tests that DecodeSeq ends iteration with an error for invalid input or cases
+func TestDecodeSeqErrors(t *testing.T) {
+	circle := sumtype.On(func(c *CircleShape) error { return nil })
+	tests := map[string]struct {
+		in    string
+		cases []sumtype.Case[error]
+	}{
+		"not an array":     {in: `{}`},
+		"malformed":        {in: `[{"kind":"circle"}, !]`},
+		"truncated":        {in: `[{"kind":"circle"}`},
+		"incomplete cases": {in: `[]`, cases: []sumtype.Case[error]{circle}},
+	}
+	for name, tt := range tests {
+		if s, err := last(sumtype.DecodeSeq[shape](strings.NewReader(tt.in), tt.cases...)); s != nil || err == nil {
+			t.Errorf("%s: expected a final nil shape and an error, got %v, %v", name, s, err)
+		}
+	}
+
+	type unregistered struct{ sumtype.Caster[unregistered] }
+	def := sumtype.Default(func(*unregistered) error { return nil })
+	if _, err := last(sumtype.DecodeSeq[unregistered](strings.NewReader(`[]`), def)); !errors.Is(err, sumtype.ErrNotRegistered) {
+		t.Errorf("Expected ErrNotRegistered, got %v", err)
+	}
+}
+
+// last returns the last element and error yielded by seq
+func last[T any](seq iter.Seq2[*T, error]) (s *T, err error) {
+	for s, err = range seq {
+	}
+	return s, err
+}
@@ -0,0 +1,72 @@
+package sumtype
+
+import (
+	"bytes"
+	"encoding/json/jsontext"
+	"reflect"
+)
+
+// UnmarshalStrict unmarshals JSON data to the Json struct instance with UnmarshalJSON and then
+// uses the discriminator to reject members irrelevant to the decoded kind: members for Json
+// fields that the kind's registered projection doesn't export. In that case, it returns an
+// *IrrelevantFieldsError listing the offending JSON member names; the Json struct instance
+// still holds everything decoded. Members aren't checked if the kind is nil or unregistered.
+// Json must be registered (see Register); otherwise UnmarshalStrict returns ErrNotRegistered.
+func (c *Caster[Json]) UnmarshalStrict(data []byte) error {
+	r, err := registrationFor[Json]()
+	if err != nil {
+		return err
+	}
+	if err := c.UnmarshalJSON(data); err != nil {
+		return err
+	}
+	kind, ok := r.kind(reflect.ValueOf(c.Json()).Elem())
+	if !ok {
+		return nil
+	}
+	projection, ok := r.projections[kind]
+	if !ok {
+		return nil
+	}
+
+	irrelevant, jsonType := map[string]bool{}, reflect.TypeFor[Json]()
+	for _, f := range hiddenFields(jsonType, projection) {
+		if name, ok := jsonName(jsonType.Field(f)); ok {
+			irrelevant[name] = true
+		}
+	}
+	names, err := memberNames(data)
+	if err != nil {
+		return err
+	}
+	var offending []string
+	for _, name := range names {
+		if irrelevant[name] {
+			offending = append(offending, name)
+		}
+	}
+	if len(offending) > 0 {
+		return &IrrelevantFieldsError{Kind: kind, Names: offending}
+	}
+	return nil
+}
+
+// memberNames returns the names of the top-level members of the JSON object in data.
+func memberNames(data []byte) ([]string, error) {
+	dec := jsontext.NewDecoder(bytes.NewReader(data))
+	if tok, err := dec.ReadToken(); err != nil || tok.Kind() != '{' {
+		return nil, err // json.Unmarshal already succeeded so data must be an object or null
+	}
+	var names []string
+	for dec.PeekKind() != '}' {
+		name, err := dec.ReadToken()
+		if err != nil {
+			return nil, err
+		}
+		names = append(names, name.String()) // Get the name before the next call voids the token
+		if err := dec.SkipValue(); err != nil {
+			return nil, err
+		}
+	}
+	return names, nil
+}
@@ -0,0 +1,48 @@
+package sumtype_test
+
+import (
+	"errors"
+	"slices"
+	"testing"
+
+	"github.com/JeffreyRichter/sumtype"
+)
+
+// TestUnmarshalStrict tests that strict unmarshaling rejects members irrelevant to the kind
+func TestUnmarshalStrict(t *testing.T) {
+	tests := []struct {
+		name     string
+		jsonData string
+		want     []string // want are the expected offending names; nil means no error
+	}{
+		{name: "Circle", jsonData: `{"kind":"circle","color":"red","radius":5}`},
+		{name: "Rectangle", jsonData: `{"kind":"rectangle","width":5,"height":null}`},
+		{name: "Circle with width", jsonData: `{"kind":"circle","width":5,"radius":1,"height":7}`, want: []string{"width", "height"}},
+		{name: "Rectangle with radius", jsonData: `{"radius":5,"kind":"rectangle"}`, want: []string{"radius"}},
+		{name: "Unknown kind", jsonData: `{"kind":"triangle","radius":5,"width":5}`},
+		{name: "No kind", jsonData: `{"radius":5,"width":5}`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var s Shape
+			err := s.caster().UnmarshalStrict([]byte(tt.jsonData))
+			var irrelevant *sumtype.IrrelevantFieldsError
+			switch {
+			case tt.want == nil && err != nil:
+				t.Errorf("Unexpected error: %v", err)
+			case tt.want != nil && !errors.As(err, &irrelevant):
+				t.Errorf("Expected an *IrrelevantFieldsError, got %v", err)
+			case tt.want != nil && !slices.Equal(irrelevant.Names, tt.want):
+				t.Errorf("Expected names %v, got %v", tt.want, irrelevant.Names)
+			}
+		})
+	}
+}
+
+// TestUnmarshalStrictInvalidJSON tests that strict unmarshaling reports syntax errors
+func TestUnmarshalStrictInvalidJSON(t *testing.T) {
+	var s Shape
+	if err := s.caster().UnmarshalStrict([]byte(`{"kind":`)); err == nil {
+		t.Error("Expected an error")
+	}
+}
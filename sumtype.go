@@ -3,13 +3,22 @@ package sumtype
 import (
 	"encoding/json/jsontext"
 	"encoding/json/v2"
+	"errors"
 	"fmt"
 	"reflect"
 	"unsafe"
 )
 
-// Cast casts From a caster To another sum type projection type.
-func Cast[To any, From any](caster *Caster[From]) *To { return (*To)(unsafe.Pointer(caster)) }
+// Cast casts From a caster To another sum type projection type. When built with the
+// sumtype_checked build tag, Cast panics unless To is From itself or a projection that
+// ValidateStructFields or Register validated for From, catching misuse in tests rather than via
+// memory corruption.
+func Cast[To any, From any](caster *Caster[From]) *To {
+	if checkedCasts {
+		checkCast[To, From]()
+	}
+	return (*To)(unsafe.Pointer(caster))
+}
 
 // Caster provides methods to cast between a sum type *Json and its variants (which all
 // have the same fields as Json). The 1st field of Json and all its variants must be a non-exported
@@ -23,34 +32,69 @@ type Caster[Json any] struct{}
 func (c *Caster[Json]) Json() *Json { return Cast[Json](c) }
 
 // MarshalJSON marshals the json struct instance to JSON
-func (c *Caster[Json]) MarshalJSON() ([]byte, error) { return json.Marshal(c.Json()) }
+func (c *Caster[Json]) MarshalJSON() ([]byte, error) { return c.MarshalWith() }
+
+// MarshalWith marshals the Json struct instance to JSON with opts (like json.Deterministic,
+// json.StringifyNumbers, or jsontext.Multiline).
+func (c *Caster[Json]) MarshalWith(opts ...json.Options) ([]byte, error) {
+	return json.Marshal(c.Json(), opts...)
+}
+
+// UnmarshalJSON unmarshals JSON data to the Json struct instance. If Json's UnknownKindPolicy is
+// UnknownKindReject, it returns an *UnknownKindError if the decoded kind isn't registered.
+func (c *Caster[Json]) UnmarshalJSON(data []byte) error { return c.UnmarshalWith(data) }
+
+// UnmarshalWith unmarshals JSON data to the Json struct instance with opts (like
+// json.RejectUnknownMembers). Like UnmarshalJSON, it returns an *UnknownKindError if Json's
+// UnknownKindPolicy is UnknownKindReject and the decoded kind isn't registered.
+func (c *Caster[Json]) UnmarshalWith(data []byte, opts ...json.Options) error {
+	if err := json.Unmarshal(data, c.Json(), opts...); err != nil {
+		return err
+	}
+	return c.checkKnownKind()
+}
 
-// UnmarshalJSON unmarshals JSON data to the Json struct instance
-func (c *Caster[Json]) UnmarshalJSON(data []byte) error { return json.Unmarshal(data, c.Json()) }
+// MarshalJSONTo marshals the Json struct instance directly to enc, honoring the caller's options
+// (like json.StringifyNumbers) and avoiding MarshalJSON's intermediate buffer.
+func (c *Caster[Json]) MarshalJSONTo(enc *jsontext.Encoder) error {
+	return json.MarshalEncode(enc, c.Json())
+}
+
+// UnmarshalJSONFrom unmarshals the next JSON value from dec to the Json struct instance, honoring
+// the caller's options (like json.RejectUnknownMembers). Like UnmarshalJSON, it returns an
+// *UnknownKindError if Json's UnknownKindPolicy is UnknownKindReject and the decoded kind isn't
+// registered.
+func (c *Caster[Json]) UnmarshalJSONFrom(dec *jsontext.Decoder) error {
+	if err := json.UnmarshalDecode(dec, c.Json()); err != nil {
+		return err
+	}
+	return c.checkKnownKind()
+}
 
-// String returns a readable JSON representation of the Json struct instance
+// String returns a readable multiline JSON representation of the Json struct instance; use
+// MarshalWith for other formatting.
 func (c *Caster[Json]) String() string {
-	j, _ := json.Marshal(c.Json(), jsontext.Multiline(true))
+	j, _ := c.MarshalWith(jsontext.Multiline(true))
 	return string(j)
 }
 
-// ZeroNonKindFields sets all fields not relevant to "Kind" to their zero value
+// ZeroNonKindFields sets all fields not relevant to "Kind" to their zero value. The fields to
+// zero are computed once per (Json, Kind struct) pair and cached.
 func (c *Caster[Json]) ZeroNonKindFields(ptrToKindStruct any) {
-	jsonFields := reflect.ValueOf(c.Json()).Elem() // Dereference the pointer to get the struct value
-	kindFields := reflect.TypeOf(ptrToKindStruct).Elem()
-	for f := range kindFields.NumField() {
-		if kindField := kindFields.Field(f); !kindField.IsExported() {
-			// kindField's unexported fields are zero'd from jsonFields' equivalent field
-			if fieldToZero := jsonFields.Field(f); fieldToZero.CanSet() {
-				fieldToZero.Set(reflect.Zero(fieldToZero.Type())) // Requires exported field
-			}
-		}
-	}
+	// kindFields' unexported fields are zero'd from the Json struct's equivalent exported field
+	zeroPlanFor(reflect.TypeFor[Json](), reflect.TypeOf(ptrToKindStruct).Elem()).zero(unsafe.Pointer(c))
 }
 
-// ValidateStructFields ensures that Json and all the specific projection types have struct fields
-// in the same order and same type. If panicOnError is true, ValidateStructFields panics if
-// there is an error, otherwise it returns the error (or nil if no error).
+// ValidateStructFields ensures that Json and all the specific projection types have the same
+// size, alignment, and 1st field (whose underlying type must be Caster[Json]), and struct fields
+// in the same order with the same type, offset, and (if exported) name. Projections must not
+// have json tags since they're marshaled as Json. Json may have one exported field tagged
+// `json:",embed"` of type jsontext.Value or map[string]T; it captures unrecognized JSON members
+// when unmarshaling and re-emits them when marshaling. A jsontext.Value keeps the members in
+// their original order; a map loses it (json.Deterministic sorts them). It also checks the
+// projections' `sumtype:"..."` constraint tags (see Caster.Validate). All problems are reported
+// together via errors.Join. If panicOnError is true, ValidateStructFields panics if there is an
+// error, otherwise it returns the error (or nil if no error).
 func (c Caster[Json]) ValidateStructFields(panicOnError bool, structs ...any) error {
 	err := c.validateStructFields(structs...)
 	if panicOnError && err != nil {
@@ -59,32 +103,101 @@ func (c Caster[Json]) ValidateStructFields(panicOnError bool, structs ...any) er
 	return err
 }
 
-// validateStructFields ensures that Json and all the specific projection types have struct fields
-// in the same order and same type. It returns nil or an error.
+// validateStructFields ensures that Json and all the specific projection types have the same
+// memory layout and compatible fields, recording the valid (Json, projection) pairs for Cast. It
+// returns nil or the errors.Join of all the problems found.
 func (c Caster[Json]) validateStructFields(structs ...any) error {
 	mainStruct := reflect.TypeFor[Json]()
+	jsonErr := errors.Join(c.validateFirstField(mainStruct), validateUnknownField(mainStruct))
+	errs := []error{jsonErr}
+	for _, otherStruct := range structs {
+		projection := reflect.TypeOf(otherStruct)
+		projectionErr := errors.Join(c.validateProjection(mainStruct, projection)...)
+		if jsonErr == nil && projectionErr == nil {
+			validatedCasts.Store([2]reflect.Type{mainStruct, projection}, true)
+		}
+		errs = append(errs, projectionErr)
+	}
+	return errors.Join(errs...)
+}
 
-	// The 1st field's underlying type must be sumtype.Converter for the unsafe casts to work
-	underlyingType, firstFieldType := reflect.TypeOf(c), mainStruct.Field(0).Type
-	if !firstFieldType.ConvertibleTo(underlyingType) || !underlyingType.ConvertibleTo(firstFieldType) {
-		return fmt.Errorf("first field of struct %s must be a type whose underlying type is %T", mainStruct.Name(), c)
+// validateFirstField ensures that the 1st field's underlying type is sumtype.Caster[Json] for the
+// unsafe casts to work. It returns nil or an error.
+func (c Caster[Json]) validateFirstField(structType reflect.Type) error {
+	underlyingType := reflect.TypeOf(c)
+	if structType.NumField() > 0 {
+		firstFieldType := structType.Field(0).Type
+		if firstFieldType.ConvertibleTo(underlyingType) && underlyingType.ConvertibleTo(firstFieldType) {
+			return nil
+		}
 	}
+	return fmt.Errorf("first field of struct %s must be a type whose underlying type is %T",
+		structType.Name(), c)
+}
 
-	for _, otherStruct := range structs {
-		otherStructType := reflect.TypeOf(otherStruct)
-		if mainStruct.NumField() != otherStructType.NumField() {
-			return fmt.Errorf("structs have different number of fields: %s=%d vs %s=%d",
-				mainStruct.Name(), mainStruct.NumField(), otherStructType.Name(), otherStructType.NumField())
+// validateProjection returns the problems that make projection incompatible with mainStruct:
+// a different size, alignment, or 1st field, fields that differ in type or offset, exported
+// fields with a different name (like swapped Width and Height fields), ignored json tags, and
+// malformed constraint tags.
+func (c Caster[Json]) validateProjection(mainStruct, projection reflect.Type) []error {
+	if projection == nil || projection.Kind() != reflect.Struct {
+		return []error{fmt.Errorf("projection must be a struct value, not %v", projection)}
+	}
+	if projection == mainStruct {
+		return nil
+	}
+	if mainStruct.NumField() != projection.NumField() {
+		return []error{fmt.Errorf("structs have different number of fields: %s=%d vs %s=%d",
+			mainStruct.Name(), mainStruct.NumField(), projection.Name(), projection.NumField())}
+	}
+
+	errs := []error{c.validateFirstField(projection)}
+	if mainStruct.Size() != projection.Size() || mainStruct.Align() != projection.Align() {
+		errs = append(errs, fmt.Errorf("structs have different size or alignment: %s=%d/%d vs %s=%d/%d",
+			mainStruct.Name(), mainStruct.Size(), mainStruct.Align(),
+			projection.Name(), projection.Size(), projection.Align()))
+	}
+	for f := range mainStruct.NumField() {
+		// Struct fields must be in same order and same type (and so at the same offset)
+		mf, of := mainStruct.Field(f), projection.Field(f)
+		switch {
+		case mf.Type != of.Type:
+			errs = append(errs, fmt.Errorf("structs have incompatible field #%d: %s.%s (%s) vs %s.%s (%s)",
+				f, mainStruct.Name(), mf.Name, mf.Type.String(), projection.Name(), of.Name, of.Type.String()))
+		case mf.Offset != of.Offset:
+			errs = append(errs, fmt.Errorf("structs have field #%d at different offsets: %s.%s=%d vs %s.%s=%d",
+				f, mainStruct.Name(), mf.Name, mf.Offset, projection.Name(), of.Name, of.Offset))
+		}
+		if of.IsExported() && of.Name != mf.Name {
+			errs = append(errs, fmt.Errorf("exported field #%d %s.%s must have the same name as %s.%s",
+				f, projection.Name(), of.Name, mainStruct.Name(), mf.Name))
+		}
+		if _, ok := of.Tag.Lookup("json"); ok {
+			errs = append(errs, fmt.Errorf("field %s.%s has a json tag which is ignored; tag %s.%s instead",
+				projection.Name(), of.Name, mainStruct.Name(), mf.Name))
 		}
+	}
+	if _, err := constraintsFor(mainStruct, projection); err != nil {
+		errs = append(errs, fmt.Errorf("struct %s: %w", projection.Name(), err))
+	}
+	return errs
+}
 
-		for f := range mainStruct.NumField() {
-			// Struct fields must be in same order and same type
-			mf, of := mainStruct.Field(f), otherStructType.Field(f)
-			if mf.Type != of.Type {
-				return fmt.Errorf("structs have incompatible field #%d: %s.%s (%s) vs %s.%s (%s)",
-					f, mainStruct.Name(), mf.Name, mf.Type.String(),
-					otherStructType.Name(), of.Name, of.Type.String())
-			}
+// validateUnknownField ensures that the Json struct has at most one field capturing unknown JSON
+// members and that it is exported. It returns nil or an error.
+func validateUnknownField(mainStruct reflect.Type) error {
+	unknown := ""
+	for f := range mainStruct.NumField() {
+		field := mainStruct.Field(f)
+		switch {
+		case !isFallback(field):
+		case !field.IsExported():
+			return fmt.Errorf("unknown members field %s.%s must be exported", mainStruct.Name(), field.Name)
+		case unknown != "":
+			return fmt.Errorf("struct %s has multiple unknown members fields: %s and %s",
+				mainStruct.Name(), unknown, field.Name)
+		default:
+			unknown = field.Name
 		}
 	}
 	return nil
@@ -2,7 +2,10 @@ package sumtype_test
 
 import (
 	"encoding/json/v2"
+	"strings"
 	"testing"
+
+	"github.com/JeffreyRichter/sumtype"
 )
 
 // TestShapeJSONMarshalUnmarshal tests JSON marshaling and unmarshaling for all shape types
@@ -176,6 +179,7 @@ func TestDiscriminatedUnionReading(t *testing.T) {
 				}
 
 				// Use the discriminator to access the correct type
+				//sumtype:nonexhaustive (the test only expects one kind)
 				switch *s.Kind {
 				case CircleShapeKind:
 					circle := s.Circle()
@@ -201,6 +205,7 @@ func TestDiscriminatedUnionReading(t *testing.T) {
 				}
 
 				// Use the discriminator to access the correct type
+				//sumtype:nonexhaustive (the test only expects one kind)
 				switch *s.Kind {
 				case RectangleShapeKind:
 					rectangle := s.Rectangle()
@@ -266,3 +271,150 @@ func TestUnsafePointerConversions(t *testing.T) {
 		t.Error("Modification through rectangle not reflected in circle view")
 	}
 }
+
+// TestValidateStructFieldsLayout tests that every incompatibility of a projection is reported together
+func TestValidateStructFieldsLayout(t *testing.T) {
+	//sumtype:invalid (same types as shape but Width and Height are swapped and tagged)
+	type swapped struct {
+		shapeCaster
+		Color  *string
+		Kind   *ShapeKind
+		_      *int
+		Height *int `json:"height"`
+		Width  *int
+	}
+	type wrongCaster struct {
+		caster int
+		Color  *string
+		Kind   *ShapeKind
+		_      *int
+		_      *int
+		_      *int
+	}
+	//sumtype:invalid
+	type wrongType struct {
+		shapeCaster
+		Color *string
+		Kind  *ShapeKind
+		_     *int
+		_     *int
+		_     int
+	}
+
+	err := sumtype.Caster[shape]{}.ValidateStructFields(false, swapped{}, wrongCaster{}, wrongType{}, 42)
+	want := []string{
+		"exported field #4 swapped.Height must have the same name as shape.Width",
+		"field swapped.Height has a json tag",
+		"exported field #5 swapped.Width must have the same name as shape.Height",
+		"first field of struct wrongCaster",
+		"incompatible field #0: shape.shapeCaster (sumtype_test.shapeCaster) vs wrongCaster.caster (int)",
+		"structs have different size or alignment",
+		"incompatible field #5: shape.Height (*int) vs wrongType._ (int)",
+		"projection must be a struct value, not int",
+	}
+	if err == nil {
+		t.Fatal("Expected errors")
+	}
+	for _, w := range want {
+		if !strings.Contains(err.Error(), w) {
+			t.Errorf("Expected error containing %q, got:\n%v", w, err)
+		}
+	}
+
+	if err := (sumtype.Caster[shape]{}).ValidateStructFields(false, Shape{}, CircleShape{}, RectangleShape{}, shape{}); err != nil {
+		t.Errorf("Expected no error, got %v", err)
+	}
+}
+
+// TestMarshalJSONToOptions tests that nested shapes are streamed with the caller's options
+func TestMarshalJSONToOptions(t *testing.T) {
+	shapes := []*Shape{(&Shape{}).SetCircle().Shape(), (&Shape{}).SetRectangle().Shape()}
+	shapes[0].Circle().Radius = ptr(1)
+	data, err := json.Marshal(shapes, json.StringifyNumbers(true))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expected := `[{"kind":"circle","radius":"1"},{"kind":"rectangle"}]`; string(data) != expected {
+		t.Errorf("Expected %s, got %s", expected, data)
+	}
+
+	var unmarshaled []*Shape
+	if err := json.Unmarshal(data, &unmarshaled, json.StringifyNumbers(true)); err != nil {
+		t.Fatal(err)
+	}
+	if *unmarshaled[0].Circle().Radius != 1 || *unmarshaled[1].Kind != RectangleShapeKind {
+		t.Errorf("Unexpected shapes: %v", unmarshaled)
+	}
+	err = json.Unmarshal([]byte(`[{"kind":"circle","diameter":2}]`), &unmarshaled, json.RejectUnknownMembers(true))
+	if err == nil || !strings.Contains(err.Error(), "diameter") {
+		t.Errorf("Expected an unknown member error, got %v", err)
+	}
+}
+
+// TestMarshalWith tests marshaling and unmarshaling with explicit options
+func TestMarshalWith(t *testing.T) {
+	c := (&Shape{}).SetCircle()
+	c.Radius = ptr(1)
+	data, err := c.caster().MarshalWith(json.StringifyNumbers(true))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expected := `{"kind":"circle","radius":"1"}`; string(data) != expected {
+		t.Errorf("Expected %s, got %s", expected, data)
+	}
+	if s := c.caster().String(); !strings.Contains(s, "\n") {
+		t.Errorf("Expected multiline JSON, got %s", s)
+	}
+
+	var s Shape
+	if err := s.caster().UnmarshalWith(data, json.StringifyNumbers(true)); err != nil || *s.Circle().Radius != 1 {
+		t.Errorf("Unexpected shape %v (error: %v)", s, err)
+	}
+	err = s.caster().UnmarshalWith([]byte(`{"kind":"circle","diameter":2}`), json.RejectUnknownMembers(true))
+	if err == nil || !strings.Contains(err.Error(), "diameter") {
+		t.Errorf("Expected an unknown member error, got %v", err)
+	}
+}
+
+// benchmarkShapes returns n alternating circles and rectangles
+func benchmarkShapes(n int) []*Shape {
+	shapes := make([]*Shape, n)
+	for i := range shapes {
+		if i%2 == 0 {
+			c := (&Shape{Color: ptr("red")}).SetCircle()
+			c.Radius = ptr(i)
+			shapes[i] = c.Shape()
+		} else {
+			r := (&Shape{Color: ptr("blue")}).SetRectangle()
+			r.Width, r.Height = ptr(i), ptr(i+1)
+			shapes[i] = r.Shape()
+		}
+	}
+	return shapes
+}
+
+// BenchmarkMarshalShapes measures marshaling 100k shapes streamed via MarshalJSONTo
+func BenchmarkMarshalShapes(b *testing.B) {
+	shapes := benchmarkShapes(100_000)
+	b.ReportAllocs()
+	for b.Loop() {
+		if _, err := json.Marshal(shapes); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+// BenchmarkUnmarshalShapes measures unmarshaling 100k shapes streamed via UnmarshalJSONFrom
+func BenchmarkUnmarshalShapes(b *testing.B) {
+	data, err := json.Marshal(benchmarkShapes(100_000))
+	if err != nil {
+		b.Fatal(err)
+	}
+	b.ReportAllocs()
+	for b.Loop() {
+		var shapes []*Shape
+		if err := json.Unmarshal(data, &shapes); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
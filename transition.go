@@ -0,0 +1,154 @@
+package sumtype
+
+import (
+	"fmt"
+	"reflect"
+	"slices"
+	"unsafe"
+)
+
+// Transitions restricts and hooks the kind changes SetKind makes to a sum type; set them with
+// SetTransitions. Domain objects like order states use them to reject illegal changes and to
+// carry over or initialize fields during legal ones.
+type Transitions[Json any, Kind comparable] struct {
+	// Allowed maps each kind to the kinds it may change to. If Allowed is nil, every change is
+	// allowed; otherwise a kind missing from Allowed can't change to any other kind. A Json
+	// struct whose kind is nil or unregistered (like the zero value of a non-pointer Kind) may
+	// always change to any kind.
+	Allowed map[Kind][]Kind
+
+	// Before, if not nil, is called before an allowed change; from is the zero Kind if the kind
+	// is nil. If Before returns an error, SetKind returns it and leaves j unmodified.
+	Before func(j *Json, from, to Kind) error
+
+	// After, if not nil, is called after the change (and after the fields irrelevant to the new
+	// kind are zeroed) with a shallow copy of j made before the change.
+	After func(prev, j *Json, from, to Kind)
+}
+
+// SetTransitions sets the rules and hooks SetKind applies when changing registered Json's kind,
+// replacing any previously set. Kind must be the discriminator's type and Allowed may mention
+// only registered kinds. If panicOnError is true, SetTransitions panics if there is an error,
+// otherwise it returns the error (or nil if no error).
+func SetTransitions[Json any, Kind comparable](panicOnError bool, transitions Transitions[Json, Kind]) error {
+	err := update[Json](func(r *registration) error {
+		if kindType := r.kindType(reflect.TypeFor[Json]()); kindType != reflect.TypeFor[Kind]() {
+			return fmt.Errorf("sumtype: transitions must use kind type %s, not %s", kindType, reflect.TypeFor[Kind]())
+		}
+		for from, tos := range transitions.Allowed {
+			for _, kind := range append([]Kind{from}, tos...) {
+				if _, ok := r.projections[kind]; !ok {
+					return fmt.Errorf("sumtype: transitions mention unregistered kind %v", kind)
+				}
+			}
+		}
+		r.transitions = transitions
+		return nil
+	})
+	if panicOnError && err != nil {
+		panic(err)
+	}
+	return err
+}
+
+// checkKindType returns an error unless Kind is Json's discriminator type. Functions reading or
+// writing the discriminator as a Kind must call it first: an interface Kind (like any) holding a
+// registered kind still matches it in the registration's maps.
+func checkKindType[Json any, Kind any](r *registration, kind Kind) error {
+	if kindType := r.kindType(reflect.TypeFor[Json]()); kindType != reflect.TypeFor[Kind]() {
+		return fmt.Errorf("sumtype: kind %v must be of type %s, not %s", kind, kindType, reflect.TypeFor[Kind]())
+	}
+	return nil
+}
+
+// kindType returns the discriminator's type (the Kind in a Kind or *Kind field).
+func (r *registration) kindType(json reflect.Type) reflect.Type {
+	t := json.Field(r.kindField).Type
+	if r.kindIsPtr {
+		return t.Elem()
+	}
+	return t
+}
+
+// SetKind changes caster's kind to kind, zeroes the fields irrelevant to it (like
+// ZeroNonKindFields), and returns caster cast To kind's registered projection. If the
+// discriminator is a nil *Kind, SetKind allocates it; if the kind changes, SetKind allocates a
+// new *Kind so copies of the Json struct don't observe the change. When the kind changes,
+// SetKind applies Json's Transitions (see SetTransitions): it returns a *TransitionError if the
+// change isn't allowed or Before's error, leaving caster unmodified in both cases. SetKind
+// returns an error if Kind isn't the discriminator's type, an *UnknownKindError if kind isn't
+// registered, and a *ProjectionError if To isn't kind's projection. Json must be registered (see Register); otherwise SetKind returns
+// ErrNotRegistered.
+func SetKind[To any, Json any, Kind comparable](caster *Caster[Json], kind Kind) (*To, error) {
+	r, err := registrationFor[Json]()
+	if err != nil {
+		return nil, err
+	}
+	if err := checkKindType[Json](r, kind); err != nil {
+		return nil, err
+	}
+	projection, ok := r.projections[kind]
+	if !ok {
+		return nil, &UnknownKindError{Kind: kind}
+	}
+	if to := reflect.TypeFor[To](); to != projection {
+		return nil, &ProjectionError{Kind: kind, Projection: projection, Type: to}
+	}
+
+	// Kind is the discriminator's type so the discriminator can be read and written directly
+	field := unsafe.Add(unsafe.Pointer(caster), r.kindOffset)
+	var from Kind
+	hasKind := true
+	if !r.kindIsPtr {
+		from = *(*Kind)(field)
+	} else if p := *(**Kind)(field); p != nil {
+		from = *p
+	} else {
+		hasKind = false
+	}
+	if hasKind && from == kind {
+		r.plans[kind].zero(unsafe.Pointer(caster))
+		return Cast[To](caster), nil
+	}
+
+	transitions, _ := r.transitions.(Transitions[Json, Kind])
+	if transitions.Allowed != nil && hasKind {
+		if _, registered := r.projections[from]; registered && !slices.Contains(transitions.Allowed[from], kind) {
+			return nil, &TransitionError{From: from, To: kind}
+		}
+	}
+	if transitions.Before != nil {
+		if err := transitions.Before(caster.Json(), from, kind); err != nil {
+			return nil, err
+		}
+	}
+	var prev *Json
+	if transitions.After != nil {
+		copied := *caster.Json()
+		prev = &copied
+	}
+	if r.kindIsPtr {
+		*(**Kind)(field) = &kind
+	} else {
+		*(*Kind)(field) = kind
+	}
+	r.plans[kind].zero(unsafe.Pointer(caster))
+	if transitions.After != nil {
+		transitions.After(prev, caster.Json(), from, kind)
+	}
+	return Cast[To](caster), nil
+}
+
+// MustSetKind is like SetKind but panics if SetKind returns an error. It makes a sum type's
+// SetXxx methods one line:
+//
+//	func (c *shapeCaster) SetCircle() *CircleShape {
+//		return sumtype.MustSetKind[CircleShape](c.caster(), CircleShapeKind)
+//	}
+func MustSetKind[To any, Json any, Kind comparable](caster *Caster[Json], kind Kind) *To {
+	to, err := SetKind[To](caster, kind)
+	if err != nil {
+		panic(err)
+	}
+	return to
+}
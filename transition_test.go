@@ -0,0 +1,186 @@
+package sumtype_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/JeffreyRichter/sumtype"
+)
+
+type (
+	// OrderState is the discriminator indicating which state an order is in
+	OrderState string
+
+	// order is a sum type whose states only change in the ways its Transitions allow
+	order struct {
+		orderCaster
+		State    OrderState `json:"state"`
+		Total    *int       `json:"total,omitempty"`
+		Paid     *int       `json:"paid,omitempty"`
+		Tracking *string    `json:"tracking,omitempty"`
+	}
+
+	// PendingOrder is an order that hasn't been paid
+	PendingOrder struct {
+		orderCaster
+		State OrderState
+		Total *int
+		_     *int
+		_     *string
+	}
+
+	// PaidOrder is an order that has been paid
+	PaidOrder struct {
+		orderCaster
+		State OrderState
+		Total *int
+		Paid  *int
+		_     *string
+	}
+
+	// ShippedOrder is an order that has been shipped
+	ShippedOrder struct {
+		orderCaster
+		State    OrderState
+		_        *int
+		Paid     *int
+		Tracking *string
+	}
+
+	orderCaster sumtype.Caster[order]
+)
+
+var _ = sumtype.Register[order](true, "State", map[OrderState]any{
+	"pending": PendingOrder{}, "paid": PaidOrder{}, "shipped": ShippedOrder{},
+})
+
+// setOrderTransitions sets order's transitions for the duration of the test
+func setOrderTransitions(t *testing.T, transitions sumtype.Transitions[order, OrderState]) {
+	t.Helper()
+	if err := sumtype.SetTransitions(false, transitions); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { sumtype.SetTransitions(true, sumtype.Transitions[order, OrderState]{}) })
+}
+
+// TestSetKind tests changing kinds without transitions
+func TestSetKind(t *testing.T) {
+	var s Shape
+	c, err := sumtype.SetKind[CircleShape](s.caster(), CircleShapeKind)
+	if err != nil || *s.Kind != CircleShapeKind || c.Radius != nil {
+		t.Fatalf("Unexpected circle: %v, %v", c, err)
+	}
+	c.Radius = ptr(3)
+	kind := s.Kind
+
+	r, err := sumtype.SetKind[RectangleShape](c.caster(), RectangleShapeKind)
+	if err != nil || *r.Kind != RectangleShapeKind || s.json().Radius != nil {
+		t.Fatalf("Unexpected rectangle: %v, %v", r, err)
+	}
+	if *kind != CircleShapeKind {
+		t.Errorf("Changing kind modified the previous *Kind: %s", *kind)
+	}
+
+	if _, err := sumtype.SetKind[CircleShape](r.caster(), RectangleShapeKind); err == nil {
+		t.Error("Expected an error for the wrong projection")
+	}
+	var unknown *sumtype.UnknownKindError
+	if _, err := sumtype.SetKind[CircleShape](r.caster(), ShapeKind("triangle")); !errors.As(err, &unknown) {
+		t.Errorf("Expected *UnknownKindError, got %v", err)
+	}
+
+	var none Shape
+	if _, err := sumtype.SetKind[CircleShape](none.caster(), any(CircleShapeKind)); err == nil || none.Kind != nil {
+		t.Errorf("Expected an error for a kind that isn't a ShapeKind, got %v (%v)", err, none.Kind)
+	}
+}
+
+// TestSetKindTransitions tests that SetKind rejects illegal transitions and calls the hooks for legal ones
+func TestSetKindTransitions(t *testing.T) {
+	var calls []string
+	setOrderTransitions(t, sumtype.Transitions[order, OrderState]{
+		Allowed: map[OrderState][]OrderState{"pending": {"paid"}, "paid": {"shipped"}},
+		Before: func(o *order, from, to OrderState) error {
+			calls = append(calls, "before "+string(from)+"->"+string(to))
+			if to == "paid" && o.Total == nil {
+				return errors.New("order has no total")
+			}
+			return nil
+		},
+		After: func(prev, o *order, from, to OrderState) {
+			calls = append(calls, "after "+string(from)+"->"+string(to))
+			if to == "paid" {
+				o.Paid = prev.Total // Carry the total over to the amount paid
+			}
+		},
+	})
+
+	var o order
+	caster := (*sumtype.Caster[order])(&o.orderCaster)
+	p, err := sumtype.SetKind[PendingOrder](caster, OrderState("pending")) // The zero kind isn't registered
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := sumtype.SetKind[PaidOrder](caster, OrderState("paid")); err == nil || o.State != "pending" {
+		t.Errorf("Expected Before's error leaving the order pending, got %v (%s)", err, o.State)
+	}
+	var transition *sumtype.TransitionError
+	if _, err := sumtype.SetKind[ShippedOrder](caster, OrderState("shipped")); !errors.As(err, &transition) ||
+		transition.From != OrderState("pending") || transition.To != OrderState("shipped") {
+		t.Errorf("Expected *TransitionError, got %v", err)
+	}
+
+	p.Total = ptr(10)
+	paid, err := sumtype.SetKind[PaidOrder](caster, OrderState("paid"))
+	if err != nil || *paid.Paid != 10 || *paid.Total != 10 {
+		t.Fatalf("Unexpected paid order: %+v, %v", o, err)
+	}
+	shipped, err := sumtype.SetKind[ShippedOrder](caster, OrderState("shipped"))
+	if err != nil || *shipped.Paid != 10 || o.Total != nil {
+		t.Fatalf("Unexpected shipped order: %+v, %v", o, err)
+	}
+	if _, err := sumtype.SetKind[ShippedOrder](caster, OrderState("shipped")); err != nil {
+		t.Errorf("Keeping the same kind: %v", err) // Not a transition so neither checked nor hooked
+	}
+
+	want := []string{"before ->pending", "after ->pending", "before pending->paid",
+		"before pending->paid", "after pending->paid", "before paid->shipped", "after paid->shipped"}
+	if len(calls) != len(want) {
+		t.Fatalf("Expected hook calls %q, got %q", want, calls)
+	}
+	for i := range want {
+		if calls[i] != want[i] {
+			t.Errorf("Expected hook calls %q, got %q", want, calls)
+			break
+		}
+	}
+}
+
+// TestSetTransitionsErrors tests that transitions must use the discriminator's type and registered kinds
+func TestSetTransitionsErrors(t *testing.T) {
+	if err := sumtype.SetTransitions(false, sumtype.Transitions[order, string]{}); err == nil {
+		t.Error("Expected an error for the wrong kind type")
+	}
+	allowed := map[OrderState][]OrderState{"pending": {"lost"}}
+	if err := sumtype.SetTransitions(false, sumtype.Transitions[order, OrderState]{Allowed: allowed}); err == nil {
+		t.Error("Expected an error for an unregistered kind")
+	}
+	if err := sumtype.SetTransitions(false, sumtype.Transitions[struct{ A int }, string]{}); !errors.Is(err, sumtype.ErrNotRegistered) {
+		t.Errorf("Expected ErrNotRegistered, got %v", err)
+	}
+}
+
+// TestMustSetKind tests that the one-line SetXxx methods allocate a nil kind and panic on errors
+func TestMustSetKind(t *testing.T) {
+	var s Shape
+	if c := s.SetCircle(); *c.Kind != CircleShapeKind {
+		t.Errorf("Expected a circle, got %v", c)
+	}
+
+	defer func() {
+		if _, ok := recover().(*sumtype.UnknownKindError); !ok {
+			t.Error("Expected a panic with an *UnknownKindError")
+		}
+	}()
+	sumtype.MustSetKind[CircleShape](s.caster(), ShapeKind("triangle"))
+}
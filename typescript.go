@@ -0,0 +1,170 @@
+package sumtype
+
+import (
+	"bytes"
+	"encoding/json/jsontext"
+	"encoding/json/v2"
+	"fmt"
+	"go/token"
+	"io"
+	"reflect"
+	"strings"
+	"time"
+)
+
+// TypeScript writes TypeScript declarations for registered Json to w so front-end code can mirror
+// the sum type. common is the public projection exposing the fields shared by all kinds (pass a
+// zero value like Shape{}). TypeScript writes:
+//   - the discriminator's type as a union of the kinds' string (or number) literals,
+//   - an interface for common with the shared members,
+//   - an interface per kind's projection extending common's interface with the kind-specific
+//     members and narrowing the discriminator to the kind's literal, and
+//   - a discriminated union of the kinds' interfaces named after common with a "Variant" suffix.
+//
+// Interfaces are named after their projection types and members are named by the Json struct's
+// json tags; pointer and omitempty/omitzero fields (except the discriminator) are optional. Json
+// must be registered (see Register); otherwise TypeScript returns ErrNotRegistered.
+func TypeScript[Json any](w io.Writer, common any) error {
+	r, err := registrationFor[Json]()
+	if err != nil {
+		return err
+	}
+	if err := (Caster[Json]{}).validateStructFields(common); err != nil {
+		return err
+	}
+	jsonType, commonType := reflect.TypeFor[Json](), reflect.TypeOf(common)
+	kindField := jsonType.Field(r.kindField)
+	if _, ok := jsonName(kindField); !ok || !commonType.Field(r.kindField).IsExported() {
+		return fmt.Errorf("the discriminator must be a JSON member exported by %s", commonType.Name())
+	}
+	kindType := kindField.Type
+	if r.kindIsPtr {
+		kindType = kindType.Elem()
+	}
+	kindTypeName := kindType.Name()
+	if kindType.PkgPath() == "" { // The discriminator's type is a predeclared type like string
+		kindTypeName = commonType.Name() + "Kind"
+	}
+
+	var b bytes.Buffer
+	b.WriteString("// Code generated by sumtype.TypeScript; DO NOT EDIT.\n\n")
+	literals, variants := make([]string, len(r.kinds)), make([]string, len(r.kinds))
+	for i, kind := range r.kinds {
+		literal, err := json.Marshal(kind)
+		if err != nil {
+			return err
+		}
+		literals[i], variants[i] = string(literal), r.projections[kind].Name()
+	}
+	fmt.Fprintf(&b, "export type %s = %s;\n", kindTypeName, strings.Join(literals, " | "))
+
+	// member writes Json's field f as an interface member whose type is typ (or, if typ is "", its
+	// TypeScript type)
+	member := func(f int, typ string) {
+		field := jsonType.Field(f)
+		name, _ := jsonName(field)
+		name = tsName(name)
+		optional := field.Type.Kind() == reflect.Pointer ||
+			hasJSONOption(field, "omitempty") || hasJSONOption(field, "omitzero")
+		if typ == "" {
+			typ = tsType(field.Type, map[reflect.Type]bool{})
+		}
+		if f == r.kindField {
+			optional = false // Like JSONSchema, the discriminator is required
+		}
+		if optional {
+			name += "?"
+		}
+		fmt.Fprintf(&b, "  %s: %s;\n", name, typ)
+	}
+
+	fmt.Fprintf(&b, "\nexport interface %s {\n", commonType.Name())
+	for f := range jsonType.NumField() {
+		if _, ok := jsonName(jsonType.Field(f)); ok && commonType.Field(f).IsExported() {
+			if f == r.kindField {
+				member(f, kindTypeName)
+				continue
+			}
+			member(f, "")
+		}
+	}
+	b.WriteString("}\n")
+
+	for i, kind := range r.kinds {
+		projection := r.projections[kind]
+		fmt.Fprintf(&b, "\nexport interface %s extends %s {\n", projection.Name(), commonType.Name())
+		member(r.kindField, literals[i])
+		for f := range jsonType.NumField() {
+			_, ok := jsonName(jsonType.Field(f))
+			if ok && projection.Field(f).IsExported() && !commonType.Field(f).IsExported() {
+				member(f, "")
+			}
+		}
+		b.WriteString("}\n")
+	}
+	fmt.Fprintf(&b, "\nexport type %sVariant = %s;\n", commonType.Name(), strings.Join(variants, " | "))
+	_, err = w.Write(b.Bytes())
+	return err
+}
+
+// tsType returns the TypeScript type for values of type t as marshaled by encoding/json/v2.
+// visiting contains the struct types being described to stop recursion on recursive types.
+func tsType(t reflect.Type, visiting map[reflect.Type]bool) string {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	switch t {
+	case reflect.TypeFor[time.Time]():
+		return "string"
+	case reflect.TypeFor[jsontext.Value]():
+		return "unknown"
+	}
+
+	switch t.Kind() {
+	case reflect.String:
+		return "string"
+	case reflect.Bool:
+		return "boolean"
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr,
+		reflect.Float32, reflect.Float64:
+		return "number"
+	case reflect.Slice, reflect.Array:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return "string" // Base64
+		}
+		elem := tsType(t.Elem(), visiting)
+		if strings.ContainsAny(elem, " |") {
+			elem = "(" + elem + ")"
+		}
+		return elem + "[]"
+	case reflect.Map:
+		return "Record<string, " + tsType(t.Elem(), visiting) + ">"
+	case reflect.Struct:
+		if visiting[t] {
+			return "unknown"
+		}
+		visiting[t] = true
+		defer delete(visiting, t)
+		members := []string{}
+		for f := range t.NumField() {
+			if name, ok := jsonName(t.Field(f)); ok {
+				members = append(members, tsName(name)+": "+tsType(t.Field(f).Type, visiting))
+			}
+		}
+		if len(members) == 0 {
+			return "Record<string, unknown>"
+		}
+		return "{ " + strings.Join(members, "; ") + " }"
+	}
+	return "unknown" // Any JSON value
+}
+
+// tsName returns the JSON member name as a TypeScript property name, quoting it if necessary.
+func tsName(name string) string {
+	if token.IsIdentifier(name) {
+		return name
+	}
+	quoted, _ := jsontext.AppendQuote(nil, name)
+	return string(quoted)
+}
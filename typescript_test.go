@@ -0,0 +1,53 @@
+package sumtype_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/JeffreyRichter/sumtype"
+)
+
+// TestTypeScript tests the TypeScript declarations generated for the registered shape sum type
+func TestTypeScript(t *testing.T) {
+	var b strings.Builder
+	if err := sumtype.TypeScript[shape](&b, Shape{}); err != nil {
+		t.Fatal(err)
+	}
+	want := `// Code generated by sumtype.TypeScript; DO NOT EDIT.
+
+export type ShapeKind = "circle" | "rectangle";
+
+export interface Shape {
+  color?: string;
+  kind: ShapeKind;
+}
+
+export interface CircleShape extends Shape {
+  kind: "circle";
+  radius?: number;
+}
+
+export interface RectangleShape extends Shape {
+  kind: "rectangle";
+  width?: number;
+  height?: number;
+}
+
+export type ShapeVariant = CircleShape | RectangleShape;
+`
+	if got := b.String(); got != want {
+		t.Errorf("Expected:\n%s\ngot:\n%s", want, got)
+	}
+}
+
+// TestTypeScriptErrors tests that TypeScript rejects unregistered types and bad common projections
+func TestTypeScriptErrors(t *testing.T) {
+	var b strings.Builder
+	if err := sumtype.TypeScript[struct{ A int }](&b, Shape{}); !errors.Is(err, sumtype.ErrNotRegistered) {
+		t.Errorf("Expected ErrNotRegistered, got %v", err)
+	}
+	if err := sumtype.TypeScript[shape](&b, struct{ A int }{}); err == nil {
+		t.Error("Expected an error for a mismatched common projection")
+	}
+}
@@ -0,0 +1,96 @@
+package sumtype_test
+
+import (
+	"encoding/json/jsontext"
+	"encoding/json/v2"
+	"testing"
+
+	"github.com/JeffreyRichter/sumtype"
+)
+
+type (
+	// pet is a sum type whose Unknown field preserves JSON members it doesn't recognize
+	pet struct {
+		petCaster
+		Kind    *string        `json:"kind"`
+		Name    *string        `json:"name,omitempty"`
+		Barks   *bool          `json:"barks,omitempty"`
+		Unknown jsontext.Value `json:",embed"`
+	}
+
+	// Dog is a pet that barks
+	Dog struct {
+		petCaster
+		Kind    *string
+		Name    *string
+		Barks   *bool `sumtype:"required"`
+		Unknown jsontext.Value
+	}
+
+	// Cat is a pet that doesn't bark
+	Cat struct {
+		petCaster
+		Kind    *string
+		Name    *string
+		_       *bool
+		Unknown jsontext.Value
+	}
+
+	petCaster sumtype.Caster[pet]
+)
+
+var _ = sumtype.Register[pet](true, "Kind", map[string]any{"dog": Dog{}, "cat": Cat{}})
+
+// TestUnknownMembersRoundTrip tests that unknown members survive unmarshaling, changing kind, and marshaling
+func TestUnknownMembersRoundTrip(t *testing.T) {
+	in := `{"kind":"dog","zebra":{"b":1,"a":2},"name":"Rex","barks":true,"apple":[1,2]}`
+	var p pet
+	if err := json.Unmarshal([]byte(in), &p); err != nil {
+		t.Fatal(err)
+	}
+	if string(p.Unknown) != `{"zebra":{"b":1,"a":2},"apple":[1,2]}` {
+		t.Errorf("Unexpected unknown members: %s", p.Unknown)
+	}
+
+	// Change the kind which zeroes Barks but not Unknown
+	*p.Kind = "cat"
+	p.Caster().ZeroNonKindFields(&Cat{})
+	if p.Barks != nil || p.Unknown == nil {
+		t.Errorf("Unexpected fields after changing kind: %+v", p)
+	}
+
+	out, err := json.Marshal(&p)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := `{"kind":"cat","name":"Rex","zebra":{"b":1,"a":2},"apple":[1,2]}`; string(out) != want {
+		t.Errorf("Expected %s, got %s", want, out)
+	}
+
+	if out, _ = p.Caster().MarshalNormalized(); string(out) != `{"kind":"cat","name":"Rex","zebra":{"b":1,"a":2},"apple":[1,2]}` {
+		t.Errorf("MarshalNormalized dropped unknown members: %s", out)
+	}
+}
+
+// TestValidateUnknownField tests that invalid unknown members fields are rejected
+func TestValidateUnknownField(t *testing.T) {
+	type twoUnknowns struct {
+		sumtype.Caster[twoUnknowns]
+		A jsontext.Value    `json:",embed"`
+		B map[string]string `json:",embed"`
+	}
+	if err := (sumtype.Caster[twoUnknowns]{}).ValidateStructFields(false); err == nil {
+		t.Error("Expected an error for multiple unknown members fields")
+	}
+
+	type mapUnknown struct {
+		sumtype.Caster[mapUnknown]
+		Unknown map[string]any `json:",embed"`
+	}
+	if err := (sumtype.Caster[mapUnknown]{}).ValidateStructFields(false); err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+}
+
+// Caster returns pet's underlying sumtype.Caster to access its helper methods.
+func (c *petCaster) Caster() *sumtype.Caster[pet] { return (*sumtype.Caster[pet])(c) }
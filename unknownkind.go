@@ -0,0 +1,69 @@
+package sumtype
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+)
+
+// UnknownKindPolicy specifies how a sum type handles kinds that aren't registered. Services
+// often add kinds in new versions; the policy makes forward-compatible clients behave predictably.
+type UnknownKindPolicy int
+
+const (
+	// UnknownKindPreserve accepts unregistered kinds and leaves their fields intact so re-marshaling
+	// sends back what was received; give the Json struct a `json:",embed"` jsontext.Value field
+	// to also keep members it has no fields for. This is the default policy.
+	UnknownKindPreserve UnknownKindPolicy = iota
+
+	// UnknownKindReject makes UnmarshalJSON and UnmarshalStrict return an *UnknownKindError for
+	// unregistered kinds.
+	UnknownKindReject
+
+	// UnknownKindFallback accepts unregistered kinds and projects them onto a designated fallback
+	// projection (see Variant and Match).
+	UnknownKindFallback
+)
+
+// SetUnknownKindPolicy sets how registered Json handles unregistered kinds. fallback must be a
+// projection struct value (like UnknownShape{}) for UnknownKindFallback and nil otherwise.
+// If panicOnError is true, SetUnknownKindPolicy panics if there is an error, otherwise it
+// returns the error (or nil if no error).
+func SetUnknownKindPolicy[Json any](panicOnError bool, policy UnknownKindPolicy, fallback any) error {
+	err := update[Json](func(r *registration) error {
+		switch {
+		case policy < UnknownKindPreserve || policy > UnknownKindFallback:
+			return fmt.Errorf("sumtype: invalid unknown kind policy %d", policy)
+		case (policy == UnknownKindFallback) != (fallback != nil):
+			return errors.New("sumtype: a fallback projection is required for UnknownKindFallback only")
+		case fallback != nil && reflect.TypeOf(fallback).Kind() != reflect.Struct:
+			return fmt.Errorf("sumtype: fallback projection must be a struct value, not %T", fallback)
+		}
+		if fallback != nil {
+			if err := (Caster[Json]{}).validateStructFields(fallback); err != nil {
+				return err
+			}
+		}
+		r.policy, r.fallback = policy, reflect.TypeOf(fallback)
+		return nil
+	})
+	if panicOnError && err != nil {
+		panic(err)
+	}
+	return err
+}
+
+// checkKnownKind returns an *UnknownKindError if Json's policy is UnknownKindReject and the
+// current kind isn't registered; otherwise it returns nil.
+func (c *Caster[Json]) checkKnownKind() error {
+	r, err := registrationFor[Json]()
+	if err != nil || r.policy != UnknownKindReject {
+		return nil
+	}
+	if kind, ok := r.kind(reflect.ValueOf(c.Json()).Elem()); ok {
+		if _, registered := r.projections[kind]; !registered {
+			return &UnknownKindError{Kind: kind}
+		}
+	}
+	return nil
+}
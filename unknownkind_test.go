@@ -0,0 +1,87 @@
+package sumtype_test
+
+import (
+	"encoding/json/v2"
+	"errors"
+	"testing"
+
+	"github.com/JeffreyRichter/sumtype"
+)
+
+// setUnknownKindPolicy sets shape's policy for the duration of the test
+func setUnknownKindPolicy(t *testing.T, policy sumtype.UnknownKindPolicy, fallback any) {
+	t.Helper()
+	if err := sumtype.SetUnknownKindPolicy[shape](false, policy, fallback); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { sumtype.SetUnknownKindPolicy[shape](true, sumtype.UnknownKindPreserve, nil) })
+}
+
+// TestUnknownKindPreserve tests that unknown kinds are accepted and re-marshaled intact by default
+func TestUnknownKindPreserve(t *testing.T) {
+	in := `{"color":"red","kind":"triangle","width":3}`
+	var s Shape
+	if err := json.Unmarshal([]byte(in), &s); err != nil {
+		t.Fatal(err)
+	}
+	if out, err := json.Marshal(s); err != nil || string(out) != in {
+		t.Errorf("Expected %s, got %s, %v", in, out, err)
+	}
+	if v := s.caster().Variant(); v != nil {
+		t.Errorf("Expected no variant, got %T", v)
+	}
+}
+
+// TestUnknownKindReject tests that unknown kinds are rejected when unmarshaling
+func TestUnknownKindReject(t *testing.T) {
+	setUnknownKindPolicy(t, sumtype.UnknownKindReject, nil)
+
+	var s Shape
+	var unknown *sumtype.UnknownKindError
+	if err := json.Unmarshal([]byte(`{"kind":"triangle"}`), &s); !errors.As(err, &unknown) || unknown.Kind != ShapeKind("triangle") {
+		t.Errorf("Expected an *UnknownKindError, got %v", err)
+	}
+	if err := s.caster().UnmarshalStrict([]byte(`{"kind":"triangle"}`)); !errors.As(err, &unknown) {
+		t.Errorf("Expected an *UnknownKindError from UnmarshalStrict, got %v", err)
+	}
+	if err := json.Unmarshal([]byte(`{"kind":"circle"}`), &s); err != nil {
+		t.Errorf("Unexpected error for a known kind: %v", err)
+	}
+}
+
+// TestUnknownKindFallback tests that unknown kinds are projected onto the fallback projection
+func TestUnknownKindFallback(t *testing.T) {
+	setUnknownKindPolicy(t, sumtype.UnknownKindFallback, Shape{})
+
+	s := &Shape{Kind: ptr[ShapeKind]("triangle"), Color: ptr("red")}
+	if v, ok := s.caster().Variant().(*Shape); !ok || v != s {
+		t.Errorf("Expected the *Shape fallback, got %T", s.caster().Variant())
+	}
+
+	circle := sumtype.On(func(c *CircleShape) string { return "circle" })
+	rectangle := sumtype.On(func(r *RectangleShape) string { return "rectangle" })
+	if _, err := sumtype.Match(s.caster(), circle, rectangle); err == nil {
+		t.Error("Expected an error for a missing fallback case")
+	}
+	got, err := sumtype.Match(s.caster(), circle, rectangle, sumtype.On(func(s *Shape) string { return "other " + *s.Color }))
+	if err != nil || got != "other red" {
+		t.Errorf("Expected the fallback case, got %q, %v", got, err)
+	}
+}
+
+// TestSetUnknownKindPolicyErrors tests that invalid policies are rejected
+func TestSetUnknownKindPolicyErrors(t *testing.T) {
+	tests := map[string]error{
+		"fallback without projection": sumtype.SetUnknownKindPolicy[shape](false, sumtype.UnknownKindFallback, nil),
+		"projection without fallback": sumtype.SetUnknownKindPolicy[shape](false, sumtype.UnknownKindReject, Shape{}),
+		"pointer projection":          sumtype.SetUnknownKindPolicy[shape](false, sumtype.UnknownKindFallback, &Shape{}),
+		"mismatched projection":       sumtype.SetUnknownKindPolicy[shape](false, sumtype.UnknownKindFallback, struct{ X int }{}),
+		"invalid policy":              sumtype.SetUnknownKindPolicy[shape](false, 42, nil),
+		"unregistered":                sumtype.SetUnknownKindPolicy[struct{}](false, sumtype.UnknownKindReject, nil),
+	}
+	for name, err := range tests {
+		if err == nil {
+			t.Errorf("%s: expected an error", name)
+		}
+	}
+}
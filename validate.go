@@ -0,0 +1,202 @@
+package sumtype
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+	"unicode/utf8"
+)
+
+// constraint is a projection field's parsed sumtype struct tag.
+type constraint struct {
+	field    int     // field is the index of the constrained Json field
+	path     string  // path is the JSON Pointer to the field's member
+	required bool    // required is true if the field must be non-nil (or non-zero if not nillable)
+	hasMin   bool    // hasMin is true if min applies
+	min      float64 // min is the minimum value (numbers) or length (strings, slices, maps)
+	hasMax   bool    // hasMax is true if max applies
+	max      float64 // max is the maximum value (numbers) or length (strings, slices, maps)
+}
+
+// constraintSet is a projection's constraints or the error parsing them.
+type constraintSet struct {
+	constraints []constraint
+	err         error
+}
+
+// constraintSets maps [2]reflect.Type{Json, projection} to its constraintSet.
+var constraintSets sync.Map
+
+// constraintsFor returns the cached constraints declared by projection's `sumtype:"..."` field
+// tags. The tag's comma-separated options are required, min=N and max=N; for strings, slices,
+// and maps, min and max constrain the length.
+func constraintsFor(json, projection reflect.Type) ([]constraint, error) {
+	key := [2]reflect.Type{json, projection}
+	if set, ok := constraintSets.Load(key); ok {
+		return set.(constraintSet).constraints, set.(constraintSet).err
+	}
+	var set constraintSet
+	for f := range projection.NumField() {
+		if set.err != nil {
+			break
+		}
+		tag, ok := projection.Field(f).Tag.Lookup("sumtype")
+		if !ok {
+			continue
+		}
+		var c constraint
+		c, set.err = parseConstraint(json.Field(f), projection.Field(f), tag)
+		c.field = f
+		set.constraints = append(set.constraints, c)
+	}
+	if set.err != nil {
+		set.constraints = nil
+	}
+	actual, _ := constraintSets.LoadOrStore(key, set)
+	return actual.(constraintSet).constraints, actual.(constraintSet).err
+}
+
+// parseConstraint parses the sumtype tag of projection field pf whose Json field is jf.
+func parseConstraint(jf, pf reflect.StructField, tag string) (constraint, error) {
+	name, ok := jsonName(jf)
+	if !pf.IsExported() || !ok {
+		return constraint{}, fmt.Errorf("sumtype tag on field %s which isn't an exported JSON member", pf.Name)
+	}
+	c := constraint{path: "/" + strings.NewReplacer("~", "~0", "/", "~1").Replace(name)}
+	t := pf.Type
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	for option := range strings.SplitSeq(tag, ",") {
+		key, value, _ := strings.Cut(option, "=")
+		switch key {
+		case "required":
+			c.required = true
+			continue
+		case "min", "max":
+			if !isNumber(t) && !hasLen(t) {
+				return constraint{}, fmt.Errorf("sumtype tag option %s on field %s: %s has no value or length to compare",
+					key, pf.Name, pf.Type)
+			}
+			n, err := strconv.ParseFloat(value, 64)
+			if err != nil {
+				return constraint{}, fmt.Errorf("sumtype tag option %s on field %s: %w", key, pf.Name, err)
+			}
+			if key == "min" {
+				c.hasMin, c.min = true, n
+			} else {
+				c.hasMax, c.max = true, n
+			}
+		default:
+			return constraint{}, fmt.Errorf("unrecognized sumtype tag option %q on field %s", key, pf.Name)
+		}
+	}
+	return c, nil
+}
+
+// check returns an error for each way the Json struct's field violates the constraint.
+func (c constraint) check(json reflect.Value) []error {
+	v := json.Field(c.field)
+	switch v.Kind() {
+	case reflect.Pointer, reflect.Map, reflect.Slice, reflect.Interface:
+		if v.IsNil() {
+			if c.required {
+				return []error{&ValidationError{Path: c.path, Reason: "is required"}}
+			}
+			return nil
+		}
+	default:
+		if c.required && v.IsZero() {
+			return []error{&ValidationError{Path: c.path, Reason: "is required"}}
+		}
+	}
+	if !c.hasMin && !c.hasMax {
+		return nil // Values like bools, structs, and times have no value or length to compare
+	}
+	for v.Kind() == reflect.Pointer {
+		if v.IsNil() {
+			return nil
+		}
+		v = v.Elem()
+	}
+
+	var n float64
+	what := "value"
+	switch {
+	case v.CanInt():
+		n = float64(v.Int())
+	case v.CanUint():
+		n = float64(v.Uint())
+	case v.CanFloat():
+		n = v.Float()
+	case v.Kind() == reflect.String:
+		n, what = float64(utf8.RuneCountInString(v.String())), "length"
+	default:
+		n, what = float64(v.Len()), "length"
+	}
+	var errs []error
+	if c.hasMin && n < c.min {
+		errs = append(errs, &ValidationError{Path: c.path, Reason: fmt.Sprintf("%s must be >= %v", what, c.min)})
+	}
+	if c.hasMax && n > c.max {
+		errs = append(errs, &ValidationError{Path: c.path, Reason: fmt.Sprintf("%s must be <= %v", what, c.max)})
+	}
+	return errs
+}
+
+// isNumber returns true if t is an integer or floating-point type.
+func isNumber(t reflect.Type) bool {
+	switch t.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr,
+		reflect.Float32, reflect.Float64:
+		return true
+	}
+	return false
+}
+
+// hasLen returns true if values of type t have a length min and max can constrain.
+func hasLen(t reflect.Type) bool {
+	switch t.Kind() {
+	case reflect.String, reflect.Slice, reflect.Array, reflect.Map:
+		return true
+	}
+	return false
+}
+
+// Validate checks the constraints declared by `sumtype:"..."` struct tags on the fields of the
+// current kind's registered projection (for example, `sumtype:"required,min=0"` on
+// CircleShape.Radius); constraints declared by other kinds' projections are ignored. The options
+// are required (the field must be non-nil, or non-zero if it can't be nil), min=N and max=N (the
+// value of a number or the length of a string, slice, or map; nil pointers aren't compared).
+// Validate returns nil or the errors.Join of a *ValidationError per violation, in field order.
+// Validate returns ErrNilKind if the kind is nil and nil if the kind has no projection (see
+// SetUnknownKindPolicy). Json must be registered (see Register); otherwise Validate returns
+// ErrNotRegistered.
+func (c *Caster[Json]) Validate() error {
+	r, err := registrationFor[Json]()
+	if err != nil {
+		return err
+	}
+	json := reflect.ValueOf(c.Json()).Elem()
+	kind, ok := r.kind(json)
+	if !ok {
+		return ErrNilKind
+	}
+	projection, ok := r.projection(kind, ok)
+	if !ok {
+		return nil
+	}
+	constraints, err := constraintsFor(json.Type(), projection)
+	if err != nil {
+		return err
+	}
+	var errs []error
+	for _, constraint := range constraints {
+		errs = append(errs, constraint.check(json)...)
+	}
+	return errors.Join(errs...)
+}
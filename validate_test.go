@@ -0,0 +1,107 @@
+package sumtype_test
+
+import (
+	"encoding/json/jsontext"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/JeffreyRichter/sumtype"
+)
+
+// TestValidate tests that only the current kind's projection constraints are checked
+func TestValidate(t *testing.T) {
+	tests := map[string]struct {
+		json string
+		want []string // want are the expected "path reason" of each *ValidationError
+	}{
+		"valid circle":            {`{"kind":"circle","radius":0}`, nil},
+		"missing radius":          {`{"kind":"circle"}`, []string{"/radius is required"}},
+		"negative radius":         {`{"kind":"circle","radius":-1}`, []string{"/radius value must be >= 0"}},
+		"other kind's fields":     {`{"kind":"circle","radius":1,"width":-5}`, nil},
+		"missing width & height":  {`{"kind":"rectangle"}`, []string{"/width is required", "/height is required"}},
+		"negative width & height": {`{"kind":"rectangle","width":-1,"height":-2}`, []string{"/width value must be >= 0", "/height value must be >= 0"}},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			var s Shape
+			if err := s.UnmarshalJSON([]byte(test.json)); err != nil {
+				t.Fatal(err)
+			}
+			err := s.caster().Validate()
+			var got []string
+			for _, e := range unjoin(err) {
+				var ve *sumtype.ValidationError
+				if !errors.As(e, &ve) {
+					t.Fatalf("Expected a *ValidationError, got %v", e)
+				}
+				got = append(got, ve.Path+" "+ve.Reason)
+			}
+			if strings.Join(got, ";") != strings.Join(test.want, ";") {
+				t.Errorf("Expected %q, got %q", test.want, got)
+			}
+		})
+	}
+
+	var s Shape
+	if err := s.caster().Validate(); !errors.Is(err, sumtype.ErrNilKind) {
+		t.Errorf("Expected ErrNilKind, got %v", err)
+	}
+}
+
+// TestValidateRequiredOnly tests that required-only constraints accept set values with no length
+func TestValidateRequiredOnly(t *testing.T) {
+	var p pet
+	if err := p.Caster().UnmarshalJSON([]byte(`{"kind":"dog","barks":false}`)); err != nil {
+		t.Fatal(err)
+	}
+	if err := p.Caster().Validate(); err != nil {
+		t.Errorf("Expected no error, got %v", err)
+	}
+	p.Barks = nil
+	var ve *sumtype.ValidationError
+	if err := p.Caster().Validate(); !errors.As(err, &ve) || ve.Path != "/barks" {
+		t.Errorf("Expected a *ValidationError for /barks, got %v", err)
+	}
+}
+
+// unjoin returns the errors joined by errors.Join (or err itself if it's not joined).
+func unjoin(err error) []error {
+	if joined, ok := err.(interface{ Unwrap() []error }); ok {
+		return joined.Unwrap()
+	}
+	if err == nil {
+		return nil
+	}
+	return []error{err}
+}
+
+// TestValidateStructFieldsConstraints tests that malformed constraint tags are rejected
+func TestValidateStructFieldsConstraints(t *testing.T) {
+	type badOption struct {
+		petCaster
+		Kind    *string
+		Name    *string `sumtype:"unique"`
+		Barks   *bool
+		Unknown jsontext.Value
+	}
+	type badMin struct {
+		petCaster
+		Kind    *string
+		Name    *string
+		Barks   *bool `sumtype:"min=1"`
+		Unknown jsontext.Value
+	}
+	type hidden struct {
+		petCaster
+		Kind    *string
+		Name    *string
+		_       *bool `sumtype:"required"`
+		Unknown jsontext.Value
+	}
+	for _, projection := range []any{badOption{}, badMin{}, hidden{}} {
+		if err := (sumtype.Caster[pet]{}).ValidateStructFields(false, projection); err == nil || !strings.Contains(err.Error(), "sumtype tag") {
+			t.Errorf("%T: expected a sumtype tag error, got %v", projection, err)
+		}
+	}
+}
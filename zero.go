@@ -0,0 +1,96 @@
+package sumtype
+
+import (
+	"reflect"
+	"sync"
+	"unsafe"
+)
+
+// zeroPlan lists the Json fields that must be zeroed for a projection's kind.
+type zeroPlan []zeroField
+
+// zeroField describes how to zero one field.
+type zeroField struct {
+	offset uintptr      // offset is the field's offset within Json
+	size   uintptr      // size is the field's size in bytes
+	typ    reflect.Type // typ is the field's type
+	how    zeroHow      // how is the fastest safe way to zero the field
+}
+
+// zeroHow indicates how to zero a field.
+type zeroHow int
+
+const (
+	zeroPointer zeroHow = iota // Write a nil pointer word (the compiler emits the GC write barrier)
+	zeroBytes                  // Clear the bytes (the field has no pointers so no write barriers are needed)
+	zeroTyped                  // Use reflect's typedmemclr for fields mixing pointers and non-pointers
+)
+
+// zeroPlans maps [2]reflect.Type{Json, projection} to its zeroPlan.
+var zeroPlans sync.Map
+
+// zeroPlanFor returns the cached plan zeroing the Json fields not exported by projection.
+func zeroPlanFor(json, projection reflect.Type) zeroPlan {
+	key := [2]reflect.Type{json, projection}
+	if plan, ok := zeroPlans.Load(key); ok {
+		return plan.(zeroPlan)
+	}
+	var plan zeroPlan
+	for _, f := range hiddenFields(json, projection) {
+		field := json.Field(f)
+		zf := zeroField{offset: field.Offset, size: field.Type.Size(), typ: field.Type, how: zeroTyped}
+		switch {
+		case isPointerWord(field.Type):
+			zf.how = zeroPointer
+		case !hasPointers(field.Type):
+			zf.how = zeroBytes
+		}
+		plan = append(plan, zf)
+	}
+	actual, _ := zeroPlans.LoadOrStore(key, plan)
+	return actual.(zeroPlan)
+}
+
+// zero zeroes the plan's fields of the Json struct at p.
+func (plan zeroPlan) zero(p unsafe.Pointer) {
+	for _, f := range plan {
+		field := unsafe.Add(p, f.offset)
+		switch f.how {
+		case zeroPointer:
+			*(*unsafe.Pointer)(field) = nil
+		case zeroBytes:
+			clear(unsafe.Slice((*byte)(field), f.size))
+		default:
+			reflect.NewAt(f.typ, field).Elem().SetZero()
+		}
+	}
+}
+
+// isPointerWord returns true if t is represented by a single pointer.
+func isPointerWord(t reflect.Type) bool {
+	switch t.Kind() {
+	case reflect.Pointer, reflect.Map, reflect.Chan, reflect.Func, reflect.UnsafePointer:
+		return true
+	}
+	return false
+}
+
+// hasPointers returns true if t's memory representation contains any pointers.
+func hasPointers(t reflect.Type) bool {
+	switch t.Kind() {
+	case reflect.Bool, reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr,
+		reflect.Float32, reflect.Float64, reflect.Complex64, reflect.Complex128:
+		return false
+	case reflect.Array:
+		return t.Len() > 0 && hasPointers(t.Elem())
+	case reflect.Struct:
+		for f := range t.NumField() {
+			if hasPointers(t.Field(f).Type) {
+				return true
+			}
+		}
+		return false
+	}
+	return true
+}
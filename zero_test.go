@@ -0,0 +1,96 @@
+package sumtype_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/JeffreyRichter/sumtype"
+)
+
+type (
+	// record is a sum type with fields of every shape ZeroNonKindFields handles differently
+	record struct {
+		recordCaster
+		Kind  *string
+		Ptr   *int
+		Num   int
+		Slice []int
+		Arr   [2]int
+		Mixed struct {
+			A int
+			B *int
+		}
+	}
+
+	// kindOnlyRecord exposes only the Kind field
+	kindOnlyRecord struct {
+		recordCaster
+		Kind  *string
+		_     *int
+		_     int
+		_     []int
+		_     [2]int
+		Mixed struct {
+			A int
+			B *int
+		}
+	}
+
+	recordCaster sumtype.Caster[record]
+)
+
+var _ = sumtype.Caster[record]{}.ValidateStructFields(true, kindOnlyRecord{})
+
+// TestZeroNonKindFields tests that every kind of hidden field is zeroed and exposed fields are kept
+func TestZeroNonKindFields(t *testing.T) {
+	r := record{Kind: ptr("k"), Ptr: ptr(1), Num: 2, Slice: []int{3}, Arr: [2]int{4, 5}}
+	r.Mixed.A, r.Mixed.B = 6, ptr(7)
+
+	for range 2 { // The 2nd time uses the cached plan
+		(*sumtype.Caster[record])(&r.recordCaster).ZeroNonKindFields(&kindOnlyRecord{})
+		if r.Ptr != nil || r.Num != 0 || r.Slice != nil || r.Arr != [2]int{} {
+			t.Errorf("Hidden fields not zeroed: %+v", r)
+		}
+		if *r.Kind != "k" || r.Mixed.A != 6 || *r.Mixed.B != 7 {
+			t.Errorf("Exposed fields modified: %+v", r)
+		}
+	}
+}
+
+// zeroNonKindFieldsReflect is ZeroNonKindFields' original implementation which walks both
+// structs with reflect on every call; it's the baseline for the benchmarks.
+func zeroNonKindFieldsReflect(ptrToJson, ptrToKindStruct any) {
+	jsonFields := reflect.ValueOf(ptrToJson).Elem()
+	kindFields := reflect.TypeOf(ptrToKindStruct).Elem()
+	for f := range kindFields.NumField() {
+		if kindField := kindFields.Field(f); !kindField.IsExported() {
+			if fieldToZero := jsonFields.Field(f); fieldToZero.CanSet() {
+				fieldToZero.Set(reflect.Zero(fieldToZero.Type()))
+			}
+		}
+	}
+}
+
+// BenchmarkZeroNonKindFields measures zeroing with the cached field plan
+func BenchmarkZeroNonKindFields(b *testing.B) {
+	s := &shape{Kind: ptr(RectangleShapeKind), Width: ptr(1), Height: ptr(2), Radius: ptr(3)}
+	for b.Loop() {
+		s.caster().ZeroNonKindFields(s.Shape())
+	}
+}
+
+// BenchmarkZeroNonKindFieldsReflect measures zeroing by walking the structs with reflect
+func BenchmarkZeroNonKindFieldsReflect(b *testing.B) {
+	s := &shape{Kind: ptr(RectangleShapeKind), Width: ptr(1), Height: ptr(2), Radius: ptr(3)}
+	for b.Loop() {
+		zeroNonKindFieldsReflect(s, s.Shape())
+	}
+}
+
+// BenchmarkSetKind measures converting between kinds which zeroes the non-kind fields
+func BenchmarkSetKind(b *testing.B) {
+	s := &shape{Kind: ptr(RectangleShapeKind)}
+	for b.Loop() {
+		s.SetCircle().SetRectangle()
+	}
+}